@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"regexp"
 	"strconv"
@@ -11,10 +10,7 @@ import (
 )
 
 func main() {
-	input := aocutil.ReadStdin()
-
-	part1(input)
-	part2(input)
+	aocutil.Run(part1, part2)
 }
 
 type Game struct {
@@ -71,7 +67,7 @@ func parseGames(input string) []Game {
 	return games
 }
 
-func part1(input string) {
+func part1(input string) int {
 	games := parseGames(input)
 
 	const wantRed = 12
@@ -89,10 +85,10 @@ searchLoop:
 		idSum += i + 1
 	}
 
-	fmt.Println(idSum)
+	return idSum
 }
 
-func part2(input string) {
+func part2(input string) int {
 	games := parseGames(input)
 	var powerSum int
 	for _, game := range games {
@@ -106,5 +102,5 @@ func part2(input string) {
 		powerSum += power
 	}
 
-	fmt.Println(powerSum)
+	return powerSum
 }
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"testing"
+
+	"libdb.so/aoc-2023/aocutil/aoctest"
+)
+
+func TestDay(t *testing.T) {
+	aoctest.ParseAndRun(t, parseCards, part1, part2)
+}
+
+func BenchmarkDay(b *testing.B) {
+	aoctest.ParseAndBench(b, parseCards, part1, part2)
+}
@@ -1,7 +1,8 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"strings"
 	"sync/atomic"
 
@@ -10,7 +11,7 @@ import (
 )
 
 func main() {
-	aocutil.Run(part1, part2)
+	aocutil.RunContext(part1, part2)
 }
 
 // SpringCondition describes the operating condition of a spring.
@@ -58,24 +59,30 @@ func (r SpringsRecord) Unfold(repeats int) SpringsRecord {
 	return new
 }
 
-func countValid(springs SpringsRecord) int {
-	type countFunc func(springs SpringsConditions, n int, damagedRuns []int) int
+// countValid counts the arrangements of springs that match the record. Debug
+// logs are scoped inside ctx. It gives up with a wrong count once ctx is
+// done.
+func countValid(ctx context.Context, springs SpringsRecord) int {
+	type countFunc func(ctx context.Context, springs SpringsConditions, n int, damagedRuns []int) int
 	var count countFunc
 	var countActual countFunc
 
 	cache := make(map[string]int)
+	var calls int
 
-	count = func(springs SpringsConditions, n int, damagedRuns []int) int {
+	count = func(ctx context.Context, springs SpringsConditions, n int, damagedRuns []int) int {
 		var count int
 
-		if !aocutil.IsSilent() {
-			log.Printf("%q %v", springs, damagedRuns)
-			oldPrefix := log.Prefix()
-			log.SetPrefix(oldPrefix + "⎸")
-			defer func() {
-				log.Printf("%d <- %q", count, springs)
-				log.SetPrefix(oldPrefix)
-			}()
+		if aocutil.Canceled(ctx, &calls) {
+			return 0
+		}
+
+		if slog.Default().Enabled(ctx, slog.LevelDebug) {
+			slog.DebugContext(ctx, "count", "springs", springs, "runs", damagedRuns)
+			defer func(ctx context.Context) {
+				slog.DebugContext(ctx, "counted", "springs", springs, "count", count)
+			}(ctx)
+			ctx = aocutil.WithLogScope(ctx, "")
 		}
 
 		key := string(springs[n:]) +
@@ -86,13 +93,13 @@ func countValid(springs SpringsRecord) int {
 			return count
 		}
 
-		count = countActual(springs, n, damagedRuns)
+		count = countActual(ctx, springs, n, damagedRuns)
 		cache[key] = count
 
 		return count
 	}
 
-	countActual = func(springs SpringsConditions, n int, damagedRuns []int) int {
+	countActual = func(ctx context.Context, springs SpringsConditions, n int, damagedRuns []int) int {
 		// Base case.
 		if n == len(springs) {
 			switch {
@@ -122,7 +129,7 @@ func countValid(springs SpringsRecord) int {
 			}
 
 			// We're still expecting more operational runs.
-			return count(springs, n+1, damagedRuns)
+			return count(ctx, springs, n+1, damagedRuns)
 
 		case Damaged:
 			if len(damagedRuns) == 0 || damagedRuns[0] == 0 {
@@ -132,16 +139,16 @@ func countValid(springs SpringsRecord) int {
 			}
 
 			damagedRuns[0]--
-			count := count(springs, n+1, damagedRuns)
+			count := count(ctx, springs, n+1, damagedRuns)
 			damagedRuns[0]++
 			return count
 
 		case Unknown:
 			// Expect either operational or damaged.
 			springs = aocutil.ReplaceStringIndex(springs, n, SpringsConditions(Operational))
-			a := count(springs, n, damagedRuns)
+			a := count(ctx, springs, n, damagedRuns)
 			springs = aocutil.ReplaceStringIndex(springs, n, SpringsConditions(Damaged))
-			b := count(springs, n, damagedRuns)
+			b := count(ctx, springs, n, damagedRuns)
 			return a + b
 
 		default:
@@ -149,26 +156,29 @@ func countValid(springs SpringsRecord) int {
 		}
 	}
 
-	return count(springs.Conditions, 0, springs.DamagedRuns)
+	return count(ctx, springs.Conditions, 0, springs.DamagedRuns)
 }
 
-func part1(input string) int {
+func part1(ctx context.Context, input string) int {
 	rows := parseInput(input)
 
 	var total int
-	for _, row := range rows {
-		total += countValid(row)
+	for i, row := range rows {
+		total += countValid(aocutil.WithLogScope(ctx, "row %d", i), row)
 	}
 
 	return total
 }
 
-func part2(input string) int {
+func part2(ctx context.Context, input string) int {
 	rows := parseInput(input)
 
 	var total atomic.Int64
-	iter.ForEach(rows, func(row *SpringsRecord) {
-		valids := countValid(row.Unfold(5))
+	iter.ForEachIdx(rows, func(i int, row *SpringsRecord) {
+		if ctx.Err() != nil {
+			return
+		}
+		valids := countValid(aocutil.WithLogScope(ctx, "row %d", i), row.Unfold(5))
 		total.Add(int64(valids))
 	})
 
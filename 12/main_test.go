@@ -1,16 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"testing"
 
 	"libdb.so/aoc-2023/aocutil"
+	"libdb.so/aoc-2023/aocutil/aoctest"
 )
 
 func TestTotalValid(t *testing.T) {
-	log.SetFlags(0)
-
 	tests := []struct {
 		in1  SpringsConditions
 		in2  []int
@@ -31,7 +30,7 @@ func TestTotalValid(t *testing.T) {
 				t.Skip()
 			}
 			in := SpringsRecord{tt.in1, tt.in2}
-			if got := countValid(in); got != tt.want {
+			if got := countValid(context.Background(), in); got != tt.want {
 				t.Errorf("TotalValid(%v) = %v, want %v", in, got, tt.want)
 				fail = true
 			}
@@ -47,6 +46,14 @@ func BenchmarkTotalValid(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		countValid(input)
+		countValid(context.Background(), input)
 	}
 }
+
+func TestDay(t *testing.T) {
+	aoctest.RunContext(t, part1, part2)
+}
+
+func BenchmarkDay(b *testing.B) {
+	aoctest.BenchContext(b, part1, part2)
+}
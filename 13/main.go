@@ -1,9 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"image"
 	"log"
-	"slices"
 	"strings"
 
 	"libdb.so/aoc-2023/aocutil"
@@ -28,10 +28,8 @@ func flipBlock(block byte) byte {
 	panic("unreachable")
 }
 
-type Map struct {
-	Data   [][]byte
-	Bounds image.Rectangle
-}
+// Map is a map of ash and rocks.
+type Map = aocutil.Map2D
 
 func parseInput(input string) []Map {
 	blocks := strings.Split(input, "\n\n")
@@ -44,49 +42,7 @@ func parseInput(input string) []Map {
 }
 
 func parseMap(input string) Map {
-	var m Map
-	lines := aocutil.SplitLines(input)
-	lines = aocutil.FilterEmptyStrings(lines)
-	m.Data = make([][]byte, len(lines))
-	for i, line := range lines {
-		m.Data[i] = []byte(line)
-	}
-	m.Bounds = image.Rect(0, 0, len(m.Data[0]), len(m.Data))
-	return m
-}
-
-// At returns the byte at the given point. If the point is out of bounds, then
-// 0 is returned.
-func (m Map) At(p image.Point) byte {
-	if !p.In(m.Bounds) {
-		return 0
-	}
-	return m.Data[p.Y][p.X]
-}
-
-// Set sets the byte at the given point.
-func (m Map) Set(p image.Point, b byte) {
-	m.Data[p.Y][p.X] = b
-}
-
-// Clone makes a copy of the map.
-func (m Map) Clone() Map {
-	var n Map
-	n.Data = make([][]byte, len(m.Data))
-	for i, line := range m.Data {
-		n.Data[i] = slices.Clone(line)
-	}
-	n.Bounds = m.Bounds
-	return n
-}
-
-func (m Map) String() string {
-	var sb strings.Builder
-	for _, line := range m.Data {
-		sb.Write(line)
-		sb.WriteByte('\n')
-	}
-	return sb.String()
+	return aocutil.NewMap2D(input)
 }
 
 // findReflections finds all reflections in the map. A reflection is a line
@@ -94,110 +50,37 @@ func (m Map) String() string {
 // Y} is returned. If the line is vertical, then {X, 0} is returned.
 func findReflections(m Map) aocutil.Iter[image.Point] {
 	return func(yield func(pt image.Point) bool) {
-		// Search vertically first. Our strategy is to compare the first line and
-		// see if any part of it is mirrored on the other side.
-	mirrorX:
-		for x := 0; x < m.Bounds.Max.X; x++ {
-			// Continue checking for Y > 0.
-			for y := 0; y < m.Bounds.Max.Y; y++ {
-				if !isMirroredAt(m, image.Pt(x, y), Vertical) {
-					continue mirrorX
+		// Search vertically first. A line between two columns is a reflection
+		// if every pair of columns around it is equal.
+		for x := m.Bounds.Min.X + 1; x < m.Bounds.Max.X; x++ {
+			if isMirroredAt(m.Col, x, m.Bounds.Min.X, m.Bounds.Max.X) {
+				if !yield(image.Pt(x, 0)) {
+					return
 				}
 			}
-			if !yield(image.Pt(x, 0)) {
-				return
-			}
 		}
 
-	mirrorY:
-		for y := 0; y < m.Bounds.Max.Y; y++ {
-			for x := 0; x < m.Bounds.Max.X; x++ {
-				if !isMirroredAt(m, image.Pt(x, y), Horizontal) {
-					continue mirrorY
+		for y := m.Bounds.Min.Y + 1; y < m.Bounds.Max.Y; y++ {
+			if isMirroredAt(m.Row, y, m.Bounds.Min.Y, m.Bounds.Max.Y) {
+				if !yield(image.Pt(0, y)) {
+					return
 				}
 			}
-			if !yield(image.Pt(0, y)) {
-				return
-			}
 		}
 	}
 }
 
-type Axis int
-
-const (
-	Horizontal Axis = iota
-	Vertical
-)
-
-func (a Axis) String() string {
-	switch a {
-	case Horizontal:
-		return "Y"
-	case Vertical:
-		return "X"
-	}
-	panic("unreachable")
-}
-
-func (a Axis) Other() Axis {
-	switch a {
-	case Horizontal:
-		return Vertical
-	case Vertical:
-		return Horizontal
-	}
-	panic("unreachable")
-}
-
-// isMirroredAt returns true if the line is mirrored at the given point.
-// atFunc is a function that returns the byte at the given index. If the index
-// is out of bounds, then it should return 0.
-func isMirroredAt(m Map, pt image.Point, axis Axis) bool {
-	switch pt {
-	case
-		m.Bounds.Min,
-		m.Bounds.Max,
-		image.Pt(m.Bounds.Min.X, m.Bounds.Max.Y),
-		image.Pt(m.Bounds.Max.X, m.Bounds.Min.Y):
-
-		// We don't check the corners.
-		return false
-	}
-
-	// log.Printf("checking at %s for axis %s", pt, axis)
-
-	var at int
-	switch axis {
-	case Horizontal:
-		at = pt.Y
-	case Vertical:
-		at = pt.X
-	}
-
-	// Search left and right simultaneously.
-	for i1, i2 := at-1, at; true; i1, i2 = i1-1, i2+1 {
-		var p1, p2 image.Point
-		switch axis {
-		case Horizontal:
-			p1 = image.Pt(pt.X, i1)
-			p2 = image.Pt(pt.X, i2)
-		case Vertical:
-			p1 = image.Pt(i1, pt.Y)
-			p2 = image.Pt(i2, pt.Y)
-		}
-		b1 := m.At(p1)
-		b2 := m.At(p2)
-		// log.Printf("  %s='%c', %s='%c'", p1, b1, p2, b2)
-		if b1 == 0 || b2 == 0 {
-			return true
-		}
-		if b1 != b2 {
-			// We're not mirrored.
+// isMirroredAt returns true if the lines are mirrored at the line between
+// at-1 and at. line returns the line at the given index, which is within
+// [min, max).
+func isMirroredAt(line func(int) []byte, at, min, max int) bool {
+	// Search both sides simultaneously until either runs out of lines.
+	for i1, i2 := at-1, at; i1 >= min && i2 < max; i1, i2 = i1-1, i2+1 {
+		if !bytes.Equal(line(i1), line(i2)) {
 			return false
 		}
 	}
-	panic("unreachable")
+	return true
 }
 
 func encodePt(pt image.Point) int {
@@ -20,52 +20,41 @@ func parseInput(input string) aocutil.Map2D {
 	return aocutil.NewMap2D(input)
 }
 
-type Direction = image.Point
-
-var (
-	North = image.Pt(0, -1)
-	South = image.Pt(0, +1)
-	East  = image.Pt(+1, 0)
-	West  = image.Pt(-1, 0)
-)
-
-// slideRock returns the destination of the rock so that it slides in the
-// direction d. If the rock cannot slide, then the destination is the same as
-// the source.
-func slideRock(m aocutil.Map2D, pt image.Point, d Direction) image.Point {
-	dst := pt.Add(d)
+// slideRock returns the destination of the rock so that it slides north. If
+// the rock cannot slide, then the destination is the same as the source.
+func slideRock(m aocutil.Map2D, pt image.Point) image.Point {
+	north := image.Pt(0, -1)
+	dst := pt.Add(north)
 	for dst.In(m.Bounds) && m.At(dst) == EmptySpace {
-		dst = dst.Add(d)
+		dst = dst.Add(north)
 	}
-	return dst.Sub(d)
+	return dst.Sub(north)
 }
 
-func tiltMap(m aocutil.Map2D, direction Direction) {
-	var d image.Point
-	var r image.Rectangle
-
-	switch direction {
-	case North, West:
-		// Scan from the top-left corner.
-		d = image.Pt(1, 1)
-		r = m.Bounds
-	case South, East:
-		// Scan from the bottom-right corner.
-		d = image.Pt(-1, -1)
-		r = image.Rectangle{
-			Min: m.Bounds.Max.Sub(image.Pt(1, 1)),
-			Max: m.Bounds.Min.Sub(image.Pt(1, 1)),
-		}
-	}
-
-	for pt := range aocutil.PointsIterateDelta(r, d) {
-		at := m.At(pt)
+// tiltNorth tilts the map so that all rounded rocks slide north. Other
+// directions are done by rotating the map first.
+func tiltNorth(m aocutil.Map2D) {
+	// Scan from the top, so that rocks further north settle first.
+	for pt, at := range m.All() {
 		if at == RoundedRock {
-			dst := slideRock(m, pt, direction)
+			dst := slideRock(m, pt)
 			m.Set(pt, EmptySpace)
 			m.Set(dst, RoundedRock)
 		}
 	}
+
+	aocutil.Checkpoint("tilt")
+}
+
+// spinCycle tilts the map north, west, south and then east. The returned map
+// has the same orientation as m.
+func spinCycle(m aocutil.Map2D) aocutil.Map2D {
+	for i := 0; i < 4; i++ {
+		// Rotating clockwise brings the next direction to the north.
+		tiltNorth(m)
+		m = m.RotateCW()
+	}
+	return m
 }
 
 func rockLoad(m aocutil.Map2D, pt image.Point) int {
@@ -84,7 +73,9 @@ func calculateTotalLoad(m aocutil.Map2D) int {
 
 func part1(input string) int {
 	m := parseInput(input)
-	tiltMap(m, North)
+	defer aocutil.WatchState("map", func() any { return m })()
+
+	tiltNorth(m)
 	return calculateTotalLoad(m)
 }
 
@@ -102,12 +93,13 @@ func part2(input string) int {
 	// cache stores the input aocutil.Map2D and the outcome of all 4 tilts.
 	cache := make(map[string]cachedMap)
 
+	var i int
+	defer aocutil.WatchState("map", func() any { return m })()
+	defer aocutil.WatchState("cycle", func() any { return i })()
+
 	const repeat = 1_000_000_000
-	for i := 0; i < repeat; i++ {
-		tiltMap(m, North)
-		tiltMap(m, West)
-		tiltMap(m, South)
-		tiltMap(m, East)
+	for i = 0; i < repeat; i++ {
+		m = spinCycle(m)
 
 		mstr := m.String()
 
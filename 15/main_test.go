@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"testing"
+
+	"libdb.so/aoc-2023/aocutil/aoctest"
+)
 
 func TestHash(t *testing.T) {
 	tests := []struct {
@@ -16,3 +20,11 @@ func TestHash(t *testing.T) {
 		}
 	}
 }
+
+func TestDay(t *testing.T) {
+	aoctest.Run(t, part1, part2)
+}
+
+func BenchmarkDay(b *testing.B) {
+	aoctest.Bench(b, part1, part2)
+}
@@ -1,27 +1,25 @@
 package main
 
 import (
+	"context"
 	"image"
-	"math"
 
 	"libdb.so/aoc-2023/aocutil"
+	"libdb.so/aoc-2023/aocutil/graph"
 )
 
 func main() {
-	aocutil.Run(part1, part2)
+	aocutil.RunContext(part1, part2)
 }
 
+// Map is a map of the heat loss of each city block.
 type Map struct {
-	aocutil.Map2D
+	aocutil.Grid[int]
 }
 
 func parseInput(input string) Map {
 	m := aocutil.NewMap2D(input)
-	return Map{m}
-}
-
-func (m Map) CostAt(pt image.Point) int {
-	return int(m.At(pt) - '0')
+	return Map{aocutil.NewGridFromMap2D(m, func(b byte) int { return int(b - '0') })}
 }
 
 func (m Map) Source() image.Point {
@@ -40,7 +38,7 @@ func turns(dir image.Point) [3]image.Point {
 	}
 }
 
-func leastCostlyPath(m Map, straightMin, straightMax int) int {
+func leastCostlyPath(ctx context.Context, m Map, straightMin, straightMax int) int {
 	type node struct {
 		pt       image.Point
 		dir      image.Point
@@ -50,63 +48,54 @@ func leastCostlyPath(m Map, straightMin, straightMax int) int {
 	start := m.Source()
 	end := m.Sink()
 
-	queue := make([]node, 0, 100)
-	queue = append(queue,
-		node{start, aocutil.VecRight, 0},
-		node{start, aocutil.VecDown, 0},
-	)
-
-	costMap := map[node]int{}
-	minCost := math.MaxInt
-
-	for len(queue) > 0 {
-		n := queue[0]
-		queue = queue[1:]
-
-		if n.pt == end && n.straight >= straightMin {
-			minCost = min(minCost, costMap[n])
-			continue
-		}
-
-		for _, dir := range turns(n.dir) {
-			if dir == n.dir && n.straight >= straightMax {
-				continue
-			}
-			if dir != n.dir && n.straight < straightMin {
-				continue
+	neighbors := func(n node) aocutil.Iter2[node, int] {
+		return func(yield func(node, int) bool) {
+			for _, dir := range turns(n.dir) {
+				if dir == n.dir && n.straight >= straightMax {
+					continue
+				}
+				if dir != n.dir && n.straight < straightMin {
+					continue
+				}
+
+				next := node{n.pt.Add(dir), dir, 1}
+				if next.dir == n.dir {
+					next.straight = n.straight + 1
+				}
+				if !next.pt.In(m.Bounds) {
+					continue
+				}
+
+				if !yield(next, m.At(next.pt)) {
+					return
+				}
 			}
-
-			next := node{n.pt.Add(dir), dir, 1}
-			if next.dir == n.dir {
-				next.straight = n.straight + 1
-			}
-			if !next.pt.In(m.Bounds) {
-				// Out of bounds or we're trying to go back.
-				continue
-			}
-
-			cost := costMap[n] + m.CostAt(next.pt)
-			if nextCost, ok := costMap[next]; ok && nextCost <= cost {
-				// We've already visited this point with a lower cost.
-				continue
-			}
-
-			costMap[next] = cost
-			queue = append(queue, next)
 		}
 	}
 
-	return minCost
+	r := graph.DijkstraContext(ctx,
+		[]node{
+			{start, aocutil.VecRight, 0},
+			{start, aocutil.VecDown, 0},
+		},
+		neighbors,
+		func(n node) bool { return n.pt == end && n.straight >= straightMin },
+	)
+	if ctx.Err() != nil {
+		return 0
+	}
+	aocutil.Assert(r.Found, "no path found")
+	return r.Cost
 }
 
-func part1(input string) int {
+func part1(ctx context.Context, input string) int {
 	m := parseInput(input)
-	c := leastCostlyPath(m, 1, 3)
+	c := leastCostlyPath(ctx, m, 1, 3)
 	return c
 }
 
-func part2(input string) int {
+func part2(ctx context.Context, input string) int {
 	m := parseInput(input)
-	c := leastCostlyPath(m, 4, 10)
+	c := leastCostlyPath(ctx, m, 4, 10)
 	return c
 }
@@ -2,28 +2,14 @@ package main
 
 import (
 	"testing"
+
+	"libdb.so/aoc-2023/aocutil/aoctest"
 )
 
-func TestCountStraightMoves(t *testing.T) {
-	tests := []struct {
-		in   pointPath
-		want int
-	}{
-		{
-			in:   pointPath{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
-			want: 3,
-		},
-		{
-			in:   pointPath{{0, 1}, {0, 0}, {1, 0}, {2, 0}, {3, 0}},
-			want: 3,
-		},
-	}
+func TestDay(t *testing.T) {
+	aoctest.RunContext(t, part1, part2)
+}
 
-	for _, tt := range tests {
-		t.Run("", func(t *testing.T) {
-			if got := tt.in.countStraightMoves(); got != tt.want {
-				t.Errorf("countStraightMoves(%v) = %v, want %v", tt.in, got, tt.want)
-			}
-		})
-	}
+func BenchmarkDay(b *testing.B) {
+	aoctest.BenchContext(b, part1, part2)
 }
@@ -20,17 +20,7 @@ func parseInput(input string) excavationPlan {
 	for _, line := range aocutil.SplitLines(input) {
 		parts := strings.Fields(line)
 		units := aocutil.Atoi[int](parts[1])
-		var direction image.Point
-		switch parts[0] {
-		case "U":
-			direction = aocutil.VecUp
-		case "D":
-			direction = aocutil.VecDown
-		case "L":
-			direction = aocutil.VecLeft
-		case "R":
-			direction = aocutil.VecRight
-		}
+		direction := aocutil.ParseDirection(parts[0]).Vec()
 		path = append(path, pos)
 		pos = pos.Add(direction.Mul(units))
 	}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"libdb.so/aoc-2023/aocutil"
+	"libdb.so/aoc-2023/aocutil/graph"
 )
 
 func main() {
@@ -74,7 +75,11 @@ func parseInput(input string) InputModules {
 func part1(input string) int {
 	system := parseInput(input)
 	var metrics ModuleSystemMetrics
-	for cycle := 1; cycle <= 1000; cycle++ {
+	var cycle int
+	defer aocutil.WatchState("metrics", func() any { return metrics })()
+	defer aocutil.WatchState("cycle", func() any { return cycle })()
+
+	for cycle = 1; cycle <= 1000; cycle++ {
 		system.Button.Push()
 		for m := range system.Tick() {
 			metrics = metrics.Accumulate(m)
@@ -86,15 +91,41 @@ func part1(input string) int {
 func part2(input string) int {
 	system := parseInput(input)
 
-	// rx will have 1 source with 4 sources.
-	rxsrcIDs := system.Modules.FindModulesWithSink("rx")
+	// rx should have a single conjunction as its source, and each of that
+	// conjunction's sources should be driven by its own counter: a strongly
+	// connected component of flip-flops and a conjunction. The counters are
+	// independent, so each of them has its own cycle.
+	adj := system.Modules.Adjacency()
+
+	rxsrcIDs := adj.Reverse()["rx"]
 	if len(rxsrcIDs) != 1 {
-		log.Println("you are probably not running the real input")
+		log.Printf("rx has %d sources instead of 1, so this is not a real input", len(rxsrcIDs))
+		return -1
+	}
+
+	rxsrc, ok := system.Modules[rxsrcIDs[0]].(*Conjunction)
+	if !ok {
+		log.Printf("rx source %q is not a conjunction, so this is not a real input", rxsrcIDs[0])
+		return -1
+	}
+
+	var counters int
+	for _, component := range graph.StronglyConnectedComponents(adj) {
+		if len(component) > 1 {
+			counters++
+		}
+	}
+	if counters != len(rxsrc.sources) {
+		log.Printf(
+			"found %d counters for the %d sources of %q, so their cycles are not independent",
+			counters, len(rxsrc.sources), rxsrc.id,
+		)
 		return -1
 	}
 
-	rxsrc := system.Modules[rxsrcIDs[0]].(*Conjunction)
 	rxsrcCycles := make([]int, len(rxsrc.sources))
+	defer aocutil.WatchState("rx cycles", func() any { return rxsrcCycles })()
+	defer aocutil.WatchCondition("rx", func() bool { return slices.Contains(rxsrc.states, Hi) })()
 
 	// Run until each of the rx conjunctions have all hi states.
 	for cycle := 1; slices.Contains(rxsrcCycles, 0); cycle++ {
@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"slices"
-	"strings"
+
+	"libdb.so/aoc-2023/aocutil/graph"
+	"libdb.so/aoc-2023/aocutil/graph/viz"
 )
 
 // Signal is a signal sent between modules.
@@ -60,31 +62,46 @@ func (b *baseModule) send(s Signal) {
 // Modules contains all modules in a module system.
 type Modules map[ModuleID]Module
 
-// AsGraphviz returns a Graphviz representation of the module system.
-func (m Modules) AsGraphviz() string {
-	var b strings.Builder
-	b.WriteString("digraph {\n")
-	b.WriteString("  button\n")
-
-	moduleName := func(m Module) string {
-		str := m.String()
-		str, _, _ = strings.Cut(str, " ")
-		str = strings.Trim(str, ":")
-		return str
+// Graph returns the module system as a graph for visualization. Flip-flops
+// are drawn as boxes, conjunctions as diamonds and the button as a circle.
+func (m Modules) Graph() *viz.Graph {
+	g := viz.New(true)
+
+	ids := make([]ModuleID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		n := g.AddNode(string(id))
+		switch m[id].(type) {
+		case *Button:
+			n.Shape = "circle"
+		case *FlipFlop:
+			n.Label = "%" + string(id)
+			n.Shape = "box"
+		case *Conjunction:
+			n.Label = "&" + string(id)
+			n.Shape = "diamond"
+		}
 	}
 
-	for _, module := range m {
-		for _, sink := range module.Sinks() {
-			fmt.Fprintf(&b,
-				"  %q -> %q;\n",
-				moduleName(module),
-				moduleName(m[sink]),
-			)
+	for _, id := range ids {
+		for _, sink := range m[id].Sinks() {
+			g.AddEdge(string(id), string(sink))
 		}
 	}
 
-	b.WriteString("}")
-	return b.String()
+	return g
+}
+
+// Adjacency returns the sinks of every module as a graph.
+func (m Modules) Adjacency() graph.Adjacency[ModuleID] {
+	adj := make(graph.Adjacency[ModuleID], len(m))
+	for id, module := range m {
+		adj[id] = module.Sinks()
+	}
+	return adj
 }
 
 // FindModulesWithSink returns all modules that have a given module as a sink.
@@ -64,6 +64,7 @@ func (s *ModuleSystem) Tick() aocutil.Iter[ModuleSystemMetrics] {
 				}
 			}
 
+			aocutil.Checkpoint("tick")
 			if !yield(metrics) {
 				return
 			}
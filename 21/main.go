@@ -1,11 +1,11 @@
 package main
 
 import (
-	"fmt"
 	"image"
 	"image/color"
 	"log"
 	"slices"
+	"time"
 
 	"libdb.so/aoc-2023/aocutil"
 )
@@ -41,13 +41,11 @@ func parseInput(input string) Map {
 	}
 }
 
-// At assumes the map is infinite in all directions.
+// At returns the byte at pt. If m.Infinite is true, then the map is repeated
+// in all directions.
 func (m Map) At(pt image.Point) byte {
 	if m.Infinite {
-		pt = image.Point{
-			X: aocutil.PositiveMod(pt.X, m.Bounds.Dx()),
-			Y: aocutil.PositiveMod(pt.Y, m.Bounds.Dy()),
-		}
+		return m.Map2D.AtTiled(pt)
 	}
 	return m.Map2D.At(pt)
 }
@@ -141,17 +139,23 @@ func part2(input string) int {
 		interests[i] = start + (size * i) // 65 + (131 * i)
 	}
 
+	recorder := aocutil.NewRecorder(time.Second)
+
 	for steps, plots := range m.TraverseSteps() {
 		if slices.Contains(interests, steps) {
 			log.Printf("steps: %d, plots: %d", steps, len(plots))
 			interestedPlots = append(interestedPlots, len(plots))
-			drawPlots(m, steps, plots)
+			if aocutil.Recording() {
+				recorder.Add(drawPlots(m, plots))
+			}
 		}
 		if len(interestedPlots) == len(interests) {
 			break
 		}
 	}
 
+	recorder.SaveRecording("21-plots.gif")
+
 	regression := aocutil.Polyfit(
 		aocutil.Range(0, float64(len(interests))).All(),
 		aocutil.Map(interestedPlots, func(i int) float64 { return float64(i) }),
@@ -166,7 +170,7 @@ func part2(input string) int {
 	return y
 }
 
-func drawPlots(m Map, steps int, plots []image.Point) {
+func drawPlots(m Map, plots []image.Point) *image.Paletted {
 	bounds := aocutil.RectangleContainingPoints(plots)
 	bounds.Min = bounds.Min.Sub(image.Point{1, 1})
 	bounds.Max = bounds.Max.Add(image.Point{1, 1})
@@ -179,10 +183,9 @@ func drawPlots(m Map, steps int, plots []image.Point) {
 		steppedMap.Set(pt, 'O')
 	}
 
-	img := steppedMap.Draw(map[byte]color.RGBA{
+	return steppedMap.Draw(map[byte]color.RGBA{
 		'.': {0, 0, 0, 255},
 		'#': {255, 0, 0, 255},
 		'O': {255, 255, 255, 255},
 	})
-	aocutil.SaveImage(img, fmt.Sprintf("output-%d.png", steps))
 }
@@ -1,17 +1,16 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"image"
-	"log"
-	"slices"
-	"strings"
 
 	. "libdb.so/aoc-2023/aocutil"
+	"libdb.so/aoc-2023/aocutil/graph"
+	"libdb.so/aoc-2023/aocutil/graph/viz"
 )
 
 func main() {
-	Run(part1, part2)
+	RunContext(part1, part2)
 }
 
 const (
@@ -66,76 +65,16 @@ func (m HikingMap) neighboringPoints(pt image.Point, ignoreSlopes bool) Iter[ima
 	}
 }
 
-type mazeGraph map[image.Point][]mazeConjunction
-
-type mazeConjunction struct {
-	pt   image.Point
-	dist int
-}
-
-func extractMazeAsGraph(m HikingMap, ignoreSlopes bool) mazeGraph {
-	type node struct {
-		pt     image.Point
-		prev   image.Point
-		origin image.Point
-		steps  int
-	}
-
-	type seenKey struct {
-		pt     image.Point
-		origin image.Point
-	}
-
-	maze := make(mazeGraph)
-	seen := NewSet[seenKey](0)
-	start := node{
-		pt:     m.Entrance,
-		origin: m.Entrance,
-		prev:   image.Point{-1, -1},
-	}
-
-	BFS(start, func(n node) []node {
-		pts := m.neighboringPoints(n.pt, ignoreSlopes).
-			Filter(func(pt image.Point) bool { return n.prev != pt }).
-			All()
-		if len(pts) == 1 {
-			// Just a normal path. Keep following it.
-			return []node{{
-				pt:     pts[0],
-				prev:   n.pt,
-				steps:  n.steps + 1,
-				origin: n.origin,
-			}}
-		}
-
-		// Conjunction
-		if !seen.Add(seenKey{n.pt, n.origin}) {
-			return nil
-		}
-
-		maze[n.origin] = append(maze[n.origin], mazeConjunction{
-			pt:   n.pt,
-			dist: n.steps,
-		})
-
-		queue := make([]node, 0, len(pts))
-		for _, pt := range pts {
-			queue = append(queue, node{
-				pt:     pt,
-				prev:   n.pt,
-				origin: n.pt,
-				steps:  1,
-			})
-		}
-
-		return queue
-	}).All()
-
-	return maze
+func extractMazeAsGraph(ctx context.Context, m HikingMap, ignoreSlopes bool) *graph.Junctions {
+	return graph.CompressContext(ctx, m.Entrance, func(pt image.Point) Iter[image.Point] {
+		return m.neighboringPoints(pt, ignoreSlopes)
+	})
 }
 
-func mazeConjunctionsToGraphviz(maze mazeGraph, entrance, exit image.Point) string {
-	ptName := func(pt image.Point) string {
+// mazeJunctionsAsGraph returns the junctions for visualization, with the
+// entrance and exit named as such.
+func mazeJunctionsAsGraph(g *graph.Junctions, entrance, exit image.Point) *viz.Graph {
+	v := g.Graph(func(pt image.Point) string {
 		switch pt {
 		case entrance:
 			return "entrance"
@@ -144,67 +83,32 @@ func mazeConjunctionsToGraphviz(maze mazeGraph, entrance, exit image.Point) stri
 		default:
 			return pt.String()
 		}
-	}
-	var b strings.Builder
-	b.WriteString("digraph G {\n")
-	for origin, pts := range maze {
-		for _, pt := range pts {
-			fmt.Fprintf(&b,
-				`  %q -> %q [label=%d]`+"\n",
-				ptName(origin), ptName(pt.pt), pt.dist)
+	})
+	for _, id := range []string{"entrance", "exit"} {
+		if n := v.Node(id); n != nil {
+			n.Shape = "box"
 		}
 	}
-	b.WriteString("}\n")
-	return b.String()
+	return v
 }
 
-func countLongestPathInGraph(maze mazeGraph, entrance, exit image.Point) int {
-	type walkingState struct {
-		path  []image.Point
-		steps int
-	}
-
-	var maxSteps int
-	stack := []walkingState{{
-		path: []image.Point{entrance},
-	}}
-
-	for len(stack) > 0 {
-		w := stack[len(stack)-1]
-		stack = stack[:len(stack)-1]
-
-		pt := w.path[len(w.path)-1]
-		if pt == exit {
-			if w.steps > maxSteps {
-				maxSteps = w.steps
-				log.Printf("found a longer path: %d steps", maxSteps)
-			}
-			continue
-		}
-
-		for _, next := range maze[pt] {
-			if slices.Contains(w.path, next.pt) {
-				continue
-			}
-			stack = append(stack, walkingState{
-				path:  append(slices.Clone(w.path), next.pt),
-				steps: w.steps + next.dist,
-			})
-		}
-	}
-
-	return maxSteps
+func countLongestPathInGraph(ctx context.Context, g *graph.Junctions, entrance, exit image.Point) int {
+	steps, ok := g.LongestPathContext(ctx, entrance, exit, graph.LongestPathOptions{
+		Parallel: true,
+	})
+	Assert(ok || ctx.Err() != nil, "no path to the exit")
+	return steps
 }
 
-func part1(input string) int {
+func part1(ctx context.Context, input string) int {
 	m := parseInput(input)
-	g := extractMazeAsGraph(m, false)
-	return countLongestPathInGraph(g, m.Entrance, m.Exit)
+	g := extractMazeAsGraph(ctx, m, false)
+	return countLongestPathInGraph(ctx, g, m.Entrance, m.Exit)
 }
 
-func part2(input string) int {
+func part2(ctx context.Context, input string) int {
 	m := parseInput(input)
-	g := extractMazeAsGraph(m, true)
-	// fmt.Print(mazeConjunctionsToGraphviz(g, m.Entrance, m.Exit))
-	return countLongestPathInGraph(g, m.Entrance, m.Exit)
+	g := extractMazeAsGraph(ctx, m, true)
+	// fmt.Print(mazeJunctionsAsGraph(g, m.Entrance, m.Exit).DOT())
+	return countLongestPathInGraph(ctx, g, m.Entrance, m.Exit)
 }
@@ -82,7 +82,7 @@ func part1(input string) int {
 	return count
 }
 
-func part2(input string) int {
+func part2(input string) string {
 	/*
 		Let (p0, v0) be the position and velocity of the rock that we'll throw
 		so that it'll hit all the hailstones at t[i], where i is the index of
@@ -136,22 +136,18 @@ func part2(input string) int {
 		vars = append(vars, fmt.Sprintf("t%d", i))
 	}
 
+	// Let Sage sum up the position too, so we can return its output as-is.
 	expr := fmt.Sprintf(
 		strings.Join([]string{
 			`var('%s')`,
-			`print(solve([%s], [%s])[0])`,
+			`s = solve([%s], [%s])[0]`,
+			`print(s[0].rhs() + s[1].rhs() + s[2].rhs())`,
 		}, "\n"),
 		strings.Join(vars, " "),
 		strings.Join(eqns, ", "),
 		strings.Join(vars, ", "))
 
-	out := sage(expr)
-	var p Point3D[int]
-	Sscanf(out,
-		"[px == %d, py == %d, pz == %d",
-		&p.X, &p.Y, &p.Z)
-
-	return p.X + p.Y + p.Z
+	return strings.TrimSpace(sage(expr))
 }
 
 func sage(input string) string {
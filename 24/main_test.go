@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+
+	"libdb.so/aoc-2023/aocutil/aoctest"
+)
+
+func TestDay(t *testing.T) {
+	parts := []aoctest.Part{aoctest.NewPart(part1), aoctest.NewPart(part2)}
+	if _, err := exec.LookPath("sage"); err != nil {
+		t.Log("sage not found, skipping part 2")
+		parts = parts[:1]
+	}
+	aoctest.TestParts(t, parts)
+}
+
+func BenchmarkDay(b *testing.B) {
+	aoctest.Bench(b, part1, part2)
+}
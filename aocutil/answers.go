@@ -0,0 +1,148 @@
+package aocutil
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"libdb.so/aoc-2023/aocutil/result"
+)
+
+// AnswersFile is the default name of the file containing the expected answers
+// of a day. It lives next to the input files.
+const AnswersFile = "answers"
+
+// Answers maps each input file name and part to its expected answer. Answers
+// files contain one answer per line in the form
+//
+//	input 1: 54078
+//	input-small 2: 281
+//
+// Blank lines and lines starting with '#' are ignored.
+type Answers map[AnswerKey]string
+
+// AnswerKey identifies an answer by its input file name and part.
+type AnswerKey struct {
+	Input string
+	Part  int
+}
+
+// String returns the key in the same format as the answers file.
+func (k AnswerKey) String() string {
+	return fmt.Sprintf("%s %d", k.Input, k.Part)
+}
+
+// LoadAnswers loads the answers file at the given path. If the file does not
+// exist, then nil is returned with no error.
+func LoadAnswers(path string) (Answers, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	answers, err := ParseAnswers(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return answers, nil
+}
+
+// ParseAnswers parses an answers file.
+func ParseAnswers(r io.Reader) (Answers, error) {
+	answers := make(Answers)
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		k, answer, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing ':'", n)
+		}
+
+		input, partStr, ok := strings.Cut(strings.TrimSpace(k), " ")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"input part: answer\"", n)
+		}
+
+		part, err := strconv.Atoi(strings.TrimSpace(partStr))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid part %q", n, partStr)
+		}
+
+		answers[AnswerKey{input, part}] = strings.TrimSpace(answer)
+	}
+
+	return answers, scanner.Err()
+}
+
+// Verify checks the given answer against the expected answer of the given
+// input and part.
+func (a Answers) Verify(input string, part int, answer string) AnswerStatus {
+	expected, ok := a[AnswerKey{input, part}]
+	if !ok {
+		return AnswerUnknown
+	}
+	if expected != answer {
+		return AnswerFail
+	}
+	return AnswerPass
+}
+
+// AnswerStatus is the result of verifying an answer.
+type AnswerStatus = result.Status
+
+const (
+	// AnswerUnknown means that there is no expected answer to compare to.
+	AnswerUnknown = result.Unknown
+	// AnswerPass means that the answer matches the expected answer.
+	AnswerPass = result.Pass
+	// AnswerFail means that the answer does not match the expected answer.
+	AnswerFail = result.Fail
+)
+
+// IdentifyInput returns the name of the input file within dir whose content is
+// the same as the given input. An empty string is returned if none matches.
+func IdentifyInput(dir, input string) string {
+	for _, name := range InputFiles(dir) {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil && bytes.Equal(b, []byte(input)) {
+			return name
+		}
+	}
+	return ""
+}
+
+// InputFiles returns the names of all input files within dir, sorted. Input
+// files are regular files starting with "input" and without an extension.
+func InputFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() &&
+			strings.HasPrefix(name, "input") &&
+			filepath.Ext(name) == "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
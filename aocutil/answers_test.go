@@ -0,0 +1,48 @@
+package aocutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestParseAnswers(t *testing.T) {
+	answers, err := ParseAnswers(strings.NewReader(`
+# input part: answer
+input 1: 54078
+input 2:  54649
+input-small 1: 142
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, Answers{
+		{"input", 1}:       "54078",
+		{"input", 2}:       "54649",
+		{"input-small", 1}: "142",
+	}, answers)
+
+	assert.Equal(t, AnswerPass, answers.Verify("input", 1, "54078"))
+	assert.Equal(t, AnswerFail, answers.Verify("input", 2, "1"))
+	assert.Equal(t, AnswerUnknown, answers.Verify("input-small", 2, "281"))
+
+	_, err = ParseAnswers(strings.NewReader("input: 1"))
+	assert.Error(t, err)
+}
+
+func TestIdentifyInput(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"input":       "1 2 3\n",
+		"input-small": "1\n",
+		"input.png":   "1 2\n",
+		"main.go":     "1 2\n",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	assert.Equal(t, "input", IdentifyInput(dir, "1 2 3\n"))
+	assert.Equal(t, "input-small", IdentifyInput(dir, "1\n"))
+	assert.Equal(t, "", IdentifyInput(dir, "1 2\n"))
+}
@@ -0,0 +1,162 @@
+// Package aoctest generates tests and benchmarks for a day from the input
+// files in its package directory.
+//
+// Every input file (see aocutil.InputFiles) is paired with the answers
+// recorded for it in the day's answers file (see aocutil.Answers). Each
+// pair of input and part with a recorded answer becomes a subtest, while
+// every input and part is benchmarked. A day's main_test.go is usually just:
+//
+//	func TestDay(t *testing.T)      { aoctest.Run(t, part1, part2) }
+//	func BenchmarkDay(b *testing.B) { aoctest.Bench(b, part1, part2) }
+package aoctest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"libdb.so/aoc-2023/aocutil"
+)
+
+// Part is a part function that works on the raw input.
+type Part func(input string) string
+
+// NewPart converts a part function into a Part. The answer is formatted
+// using aocutil.FormatAnswer.
+func NewPart[A any](part func(string) A) Part {
+	return func(input string) string { return aocutil.FormatAnswer(part(input)) }
+}
+
+// NewContextPart is like NewPart for parts that take a context, as given to
+// aocutil.RunContext. The part is given a context that is never done.
+func NewContextPart[A any](part func(context.Context, string) A) Part {
+	return func(input string) string {
+		return aocutil.FormatAnswer(part(context.Background(), input))
+	}
+}
+
+// NewParsedPart converts a part function taking the parsed input into a Part.
+// The input is parsed again every time the part is called.
+func NewParsedPart[T, A any](parse func(string) T, part func(T) A) Part {
+	return func(input string) string { return aocutil.FormatAnswer(part(parse(input))) }
+}
+
+// Parts converts the given part functions into Parts.
+func Parts[A any](parts ...func(string) A) []Part {
+	return aocutil.Map(parts, NewPart[A])
+}
+
+// Run tests the given parts against every input file with recorded answers.
+func Run[A1, A2 any](t *testing.T, p1 func(string) A1, p2 func(string) A2) {
+	TestParts(t, []Part{NewPart(p1), NewPart(p2)})
+}
+
+// RunContext is like Run for parts that take a context.
+func RunContext[A1, A2 any](t *testing.T, p1 func(context.Context, string) A1, p2 func(context.Context, string) A2) {
+	TestParts(t, []Part{NewContextPart(p1), NewContextPart(p2)})
+}
+
+// ParseAndRun is like Run, except the input is parsed first.
+func ParseAndRun[T, A1, A2 any](t *testing.T, parse func(string) T, p1 func(T) A1, p2 func(T) A2) {
+	TestParts(t, []Part{NewParsedPart(parse, p1), NewParsedPart(parse, p2)})
+}
+
+// Bench benchmarks the given parts against every input file.
+func Bench[A1, A2 any](b *testing.B, p1 func(string) A1, p2 func(string) A2) {
+	BenchParts(b, []Part{NewPart(p1), NewPart(p2)})
+}
+
+// BenchContext is like Bench for parts that take a context.
+func BenchContext[A1, A2 any](b *testing.B, p1 func(context.Context, string) A1, p2 func(context.Context, string) A2) {
+	BenchParts(b, []Part{NewContextPart(p1), NewContextPart(p2)})
+}
+
+// ParseAndBench is like Bench, except the input is parsed first. Parsing is
+// included in the measured time.
+func ParseAndBench[T, A1, A2 any](b *testing.B, parse func(string) T, p1 func(T) A1, p2 func(T) A2) {
+	BenchParts(b, []Part{NewParsedPart(parse, p1), NewParsedPart(parse, p2)})
+}
+
+// TestParts tests the given parts against every input file in the working
+// directory. Part i is the (i+1)th part. The real input is skipped in short
+// mode.
+func TestParts(t *testing.T, parts []Part) {
+	answers, err := aocutil.LoadAnswers(aocutil.AnswersFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answers == nil {
+		t.Skipf("no %s file", aocutil.AnswersFile)
+	}
+
+	silenceUnlessVerbose()
+
+	for _, input := range aocutil.InputFiles(".") {
+		t.Run(input, func(t *testing.T) {
+			if input == "input" && testing.Short() {
+				t.Skip("skipping real input in short mode")
+			}
+
+			data := aocutil.ReadFile(input)
+			for i, part := range parts {
+				n := i + 1
+				t.Run(partName(n), func(t *testing.T) {
+					want, ok := answers[aocutil.AnswerKey{Input: input, Part: n}]
+					if !ok {
+						t.Skip("no recorded answer")
+					}
+
+					got, err := call(part, data)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if got != want {
+						t.Errorf("part %d of %s = %s, want %s", n, input, got, want)
+					}
+				})
+			}
+		})
+	}
+}
+
+// BenchParts benchmarks the given parts against every input file in the
+// working directory.
+func BenchParts(b *testing.B, parts []Part) {
+	silenceUnlessVerbose()
+
+	for _, input := range aocutil.InputFiles(".") {
+		b.Run(input, func(b *testing.B) {
+			data := aocutil.ReadFile(input)
+			for i, part := range parts {
+				b.Run(partName(i+1), func(b *testing.B) {
+					for n := 0; n < b.N; n++ {
+						if _, err := call(part, data); err != nil {
+							b.Skip(err)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func partName(n int) string {
+	return fmt.Sprintf("part%d", n)
+}
+
+// call calls part, turning panics into errors so that one broken input does
+// not take down the other subtests.
+func call(part Part, input string) (answer string, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+	return part(input), nil
+}
+
+func silenceUnlessVerbose() {
+	if !testing.Verbose() {
+		aocutil.SilenceLogging()
+	}
+}
@@ -0,0 +1,48 @@
+package aoctest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// The answers in testdata are the line and word counts of each input.
+
+func lines(input string) int { return strings.Count(input, "\n") }
+func words(input string) int { return len(strings.Fields(input)) }
+
+func TestRun(t *testing.T) {
+	chdir(t, "testdata")
+	Run(t, lines, words)
+}
+
+func TestRunContext(t *testing.T) {
+	chdir(t, "testdata")
+	RunContext(t,
+		func(ctx context.Context, input string) int { return lines(input) },
+		func(ctx context.Context, input string) int { return words(input) })
+}
+
+func TestParseAndRun(t *testing.T) {
+	chdir(t, "testdata")
+	ParseAndRun(t, func(input string) []string { return strings.SplitAfter(input, "\n") },
+		func(lines []string) int { return len(lines) - 1 },
+		func(lines []string) int { return words(strings.Join(lines, "")) })
+}
+
+func BenchmarkBench(b *testing.B) {
+	chdir(b, "testdata")
+	Bench(b, lines, words)
+}
+
+func chdir(tb testing.TB, dir string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { os.Chdir(wd) })
+}
@@ -7,12 +7,12 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"math"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
-	"sync/atomic"
 	"testing"
 	"time"
 	"unsafe"
@@ -27,104 +27,72 @@ import (
 	_ "gonum.org/v1/gonum"
 )
 
-var silent atomic.Bool
-
 var (
-	part1Only = false
-	part2Only = false
+	part1Only   = false
+	part2Only   = false
+	jsonOutput  = false
+	answersPath = AnswersFile
+	timeParts   = false
+	benchRuns   = 0
+	inputNames  stringsFlag
+	allInputs   = false
+	sequential  = false
+	raceCheck   = false
+	partTimeout time.Duration
 )
 
 func init() {
-	w := &logPrefixedWriter{
-		lastTime: time.Now(),
-		noColors: false,
-	}
-
-	log.SetFlags(0)
-	log.SetOutput(w)
+	logLevel.Set(slog.LevelInfo)
 
 	if !testing.Testing() {
-		silent_ := flag.Bool("s", false, "suppress output")
+		silent := flag.Bool("s", false, "suppress logging below the warning level")
 		flag.BoolVar(&part1Only, "1", false, "run only part 1")
 		flag.BoolVar(&part2Only, "2", false, "run only part 2")
+		flag.BoolVar(&jsonOutput, "json", false, "print results as JSON lines")
+		flag.StringVar(&answersPath, "answers", answersPath, "verify against the given answers file if it exists")
+		flag.BoolVar(&timeParts, "time", false, "report the time and memory used by parsing and each part")
+		flag.IntVar(&benchRuns, "bench", 0, "run each part `N` times and report min/median/p95 durations")
+		flag.StringVar(&profiles.cpu, "cpuprofile", "", "write a CPU profile of each part to `file`")
+		flag.StringVar(&profiles.mem, "memprofile", "", "write an allocation profile of the whole run to `file`")
+		flag.StringVar(&profiles.trace, "trace", "", "write an execution trace of each part to `file`")
+		flag.StringVar(&profiles.block, "blockprofile", "", "write a goroutine blocking profile of the whole run to `file`")
+		flag.Var(&inputNames, "i", "read the input from the file `name` instead of stdin, can be repeated")
+		flag.BoolVar(&allInputs, "all-inputs", false, "run against every input file in the working directory")
+		flag.BoolVar(&sequential, "seq", false, "run the parts one after another instead of concurrently")
+		flag.BoolVar(&raceCheck, "race-check", false, "report parts that mutate their parsed input")
+		flag.DurationVar(&partTimeout, "timeout", 0, "stop each part after the given duration")
+		flag.Func("log-level", "log records of at least the given `level` (debug, info, warn or error)", func(v string) error {
+			return logLevel.UnmarshalText([]byte(v))
+		})
+		flag.BoolVar(&logJSON, "log-json", false, "log JSON lines instead of text")
+		flag.BoolVar(&stepping, "step", false, "pause at each checkpoint if stdin is a terminal")
+		flag.StringVar(&imageViewer, "image-viewer", imageViewer, "show images with `viewer`: auto, open, kitty, iterm, sixel, save or a command (default from $AOC_IMAGE_VIEWER)")
+		flag.StringVar(&imageDir, "image-dir", imageDir, "save images into `dir` (default from $AOC_IMAGE_DIR)")
+		flag.BoolVar(&recording, "record", false, "save animations made with Recorder.SaveRecording into the image directory")
 		flag.Parse()
-		silent.Store(*silent_)
-	}
 
-	if silent.Load() {
-		SilenceLogging()
-	}
-}
-
-type logPrefixedWriter struct {
-	lastTime time.Time
-	noColors bool
-}
-
-func (w *logPrefixedWriter) Write(b []byte) (n int, err error) {
-	now := time.Now()
-	d := now.Sub(w.lastTime)
-	w.lastTime = now
+		if stepping && stdinIsTerminal() {
+			steps.active.Store(true)
+		}
 
-	const f1 = "+% 3d.%06ds ⎸ "
-	const f2 = "\033[38;5;248m" + f1 + "\033[0m"
-	pf := f2
-	if w.noColors {
-		pf = f1
+		if *silent {
+			SilenceLogging()
+		}
 	}
-	p := fmt.Sprintf(pf, int(d.Seconds()), int(d.Microseconds())%1000000)
-	return writePrefixed(os.Stderr, b, []byte(p+""+log.Prefix()))
-}
 
-// ScopeLogf enters a log scope.
-func ScopeLogf(f string, v ...any) (unscope func()) {
-	old := log.Prefix()
-	new := old
-	if f != "" {
-		new += fmt.Sprintf(f, v...) + ": "
-	} else {
-		new += "."
-	}
-	log.SetPrefix(new)
-	return func() { log.SetPrefix(old) }
+	setupLogging()
 }
 
-// IsSilent returns true if the -s flag is given.
-func IsSilent() bool { return silent.Load() }
+// stringsFlag is a flag that can be given multiple times.
+type stringsFlag []string
 
-// SilenceLogging disables logging.
-func SilenceLogging() {
-	silent.Store(true)
-	log.SetOutput(io.Discard)
-}
-
-// Run runs the given functions with the stdin input.
-func Run(p1, p2 func(string) int) {
-	input := ReadStdin()
-	switch {
-	case part1Only:
-		fmt.Println(p1(input))
-	case part2Only:
-		fmt.Println(p2(input))
-	default:
-		fmt.Println(p1(input))
-		fmt.Println(p2(input))
-	}
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
 }
 
-// ParseAndRun runs the given functions with the input after parsing it.
-func ParseAndRun[T any](parse func(string) T, p1, p2 func(T) int) {
-	input := ReadStdin()
-	value := parse(input)
-	switch {
-	case part1Only:
-		fmt.Println(p1(value))
-	case part2Only:
-		fmt.Println(p2(value))
-	default:
-		fmt.Println(p1(value))
-		fmt.Println(p2(value))
-	}
+func (f *stringsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
 }
 
 // ReadFile reads a file into a string, panicking if it fails.
@@ -920,8 +888,12 @@ func Permutations[T any](slice []T, k int) Iter[[]T] {
 	}
 }
 
-// WaitForKeypress waits for a keypress.
+// WaitForKeypress waits for a keypress. It returns 0 right away if stdin is
+// not a terminal.
 func WaitForKeypress() byte {
+	if !stdinIsTerminal() {
+		return 0
+	}
 	fmt.Print("Press any key to continue...")
 	var b [1]byte
 	E2(os.Stdin.Read(b[:]))
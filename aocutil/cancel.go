@@ -0,0 +1,31 @@
+package aocutil
+
+import "context"
+
+// cancellationInterval is the number of calls to Canceled between checks of
+// its context.
+const cancellationInterval = 1 << 10
+
+// Canceled increments i and reports whether ctx is done. ctx is only checked
+// every cancellationInterval calls, so that it is cheap enough to be called
+// on every iteration of a hot loop, e.g.
+//
+//	var i int
+//	for ... {
+//		if aocutil.Canceled(ctx, &i) {
+//			return
+//		}
+//	}
+//
+// Once it has returned true, every later call with the same i returns true,
+// so recursive searches sharing i unwind right away.
+func Canceled(ctx context.Context, i *int) bool {
+	*i++
+	if *i%cancellationInterval != 0 || ctx.Err() == nil {
+		return false
+	}
+	// Stay just below the next check, so that ctx is checked again on the
+	// next call.
+	*i--
+	return true
+}
@@ -0,0 +1,25 @@
+package aocutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var n int
+	for range RangeContext(ctx, 0, 10*cancellationInterval) {
+		if n++; n == cancellationInterval/2 {
+			cancel()
+		}
+	}
+	assert.Equal(t, cancellationInterval-1, n)
+
+	i := cancellationInterval - 1
+	assert.True(t, Canceled(ctx, &i))
+	assert.True(t, Canceled(ctx, &i))
+}
@@ -0,0 +1,165 @@
+package aocutil
+
+import (
+	"fmt"
+	"image"
+)
+
+// Direction is one of the four cardinal directions. Directions are ordered
+// clockwise starting from up, so turning right adds one.
+type Direction uint8
+
+const (
+	DirUp Direction = iota
+	DirRight
+	DirDown
+	DirLeft
+)
+
+// Directions is a list of all directions in clockwise order.
+var Directions = []Direction{DirUp, DirRight, DirDown, DirLeft}
+
+var (
+	// DiagonalDirections is a list of the four diagonal vectors.
+	DiagonalDirections = []image.Point{
+		VecUp.Add(VecLeft),
+		VecUp.Add(VecRight),
+		VecDown.Add(VecLeft),
+		VecDown.Add(VecRight),
+	}
+	// AllDirections is a list of the cardinal and diagonal vectors, which
+	// give the 8 neighbors of a point.
+	AllDirections = append(append([]image.Point{}, CardinalDirections...), DiagonalDirections...)
+)
+
+// directionNames maps the accepted names of each direction to it.
+var directionNames = map[string]Direction{
+	"U": DirUp, "N": DirUp, "^": DirUp, "↑": DirUp,
+	"R": DirRight, "E": DirRight, ">": DirRight, "→": DirRight,
+	"D": DirDown, "S": DirDown, "v": DirDown, "↓": DirDown,
+	"L": DirLeft, "W": DirLeft, "<": DirLeft, "←": DirLeft,
+}
+
+// ParseDirection parses a direction from U/D/L/R, N/S/E/W or one of the
+// arrow glyphs ^v<> and ↑↓←→. It panics if s is none of them.
+func ParseDirection(s string) Direction {
+	d, ok := directionNames[s]
+	if !ok {
+		panic(fmt.Errorf("invalid direction %q", s))
+	}
+	return d
+}
+
+// DirectionOf returns the direction of the given unit vector. It returns
+// false if v is not one of VecUp, VecDown, VecLeft and VecRight.
+func DirectionOf(v image.Point) (Direction, bool) {
+	for _, d := range Directions {
+		if d.Vec() == v {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Vec returns the unit vector of the direction.
+func (d Direction) Vec() image.Point {
+	switch d % 4 {
+	case DirUp:
+		return VecUp
+	case DirRight:
+		return VecRight
+	case DirDown:
+		return VecDown
+	default:
+		return VecLeft
+	}
+}
+
+// TurnLeft returns the direction rotated 90 degrees counter-clockwise.
+func (d Direction) TurnLeft() Direction { return (d + 3) % 4 }
+
+// TurnRight returns the direction rotated 90 degrees clockwise.
+func (d Direction) TurnRight() Direction { return (d + 1) % 4 }
+
+// Reverse returns the opposite direction.
+func (d Direction) Reverse() Direction { return (d + 2) % 4 }
+
+// Arrow returns the direction as one of the glyphs ^>v<.
+func (d Direction) Arrow() byte {
+	return "^>v<"[d%4]
+}
+
+// String returns the direction as one of U, R, D and L.
+func (d Direction) String() string {
+	return string("URDL"[d%4])
+}
+
+// Neighbors returns an iterator over the points next to pt in the directions
+// given by dirs, such as CardinalDirections or AllDirections. Points outside
+// the map are skipped.
+func (m Map2D) Neighbors(pt image.Point, dirs []image.Point) Iter2[image.Point, byte] {
+	return func(yield func(image.Point, byte) bool) {
+		for _, d := range dirs {
+			n := pt.Add(d)
+			if n.In(m.Bounds) && !yield(n, m.At(n)) {
+				return
+			}
+		}
+	}
+}
+
+// NeighborsWrapped is like Neighbors, except the map wraps around at its
+// edges like a torus. The returned points are always within the map.
+func (m Map2D) NeighborsWrapped(pt image.Point, dirs []image.Point) Iter2[image.Point, byte] {
+	return func(yield func(image.Point, byte) bool) {
+		for _, d := range dirs {
+			n := m.Wrap(pt.Add(d))
+			if !yield(n, m.At(n)) {
+				return
+			}
+		}
+	}
+}
+
+// NeighborsTiled is like Neighbors, except the map is repeated infinitely in
+// all directions. Unlike NeighborsWrapped, the returned points are not
+// wrapped, so each tile has its own points.
+func (m Map2D) NeighborsTiled(pt image.Point, dirs []image.Point) Iter2[image.Point, byte] {
+	return func(yield func(image.Point, byte) bool) {
+		for _, d := range dirs {
+			n := pt.Add(d)
+			if !yield(n, m.AtTiled(n)) {
+				return
+			}
+		}
+	}
+}
+
+// Wrap returns the point within the map that pt lands on if the map is
+// repeated infinitely in all directions.
+func (m Map2D) Wrap(pt image.Point) image.Point {
+	return image.Point{
+		X: m.Bounds.Min.X + PositiveMod(pt.X-m.Bounds.Min.X, m.Bounds.Dx()),
+		Y: m.Bounds.Min.Y + PositiveMod(pt.Y-m.Bounds.Min.Y, m.Bounds.Dy()),
+	}
+}
+
+// AtTiled returns the byte at pt if the map is repeated infinitely in all
+// directions.
+func (m Map2D) AtTiled(pt image.Point) byte {
+	return m.At(m.Wrap(pt))
+}
+
+// AllAround returns an iterator over the points that surround r, including
+// the diagonal corners. Points outside the map are skipped.
+func (m Map2D) AllAround(r image.Rectangle) Iter2[image.Point, byte] {
+	r = r.Canon()
+	border := r.Inset(-1)
+	return func(yield func(image.Point, byte) bool) {
+		for pt, b := range m.AllWithin(border) {
+			if !pt.In(r) && !yield(pt, b) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package aocutil
+
+import (
+	"image"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestDirection(t *testing.T) {
+	assert.Equal(t, DirRight, DirUp.TurnRight())
+	assert.Equal(t, DirLeft, DirUp.TurnLeft())
+	assert.Equal(t, DirDown, DirUp.Reverse())
+	assert.Equal(t, DirUp, DirLeft.TurnRight())
+	assert.Equal(t, DirRight, DirLeft.Reverse())
+
+	for _, d := range Directions {
+		assert.Equal(t, d.Vec().Mul(-1), d.Reverse().Vec())
+		got, ok := DirectionOf(d.Vec())
+		assert.True(t, ok)
+		assert.Equal(t, d, got)
+		assert.Equal(t, d, ParseDirection(d.String()))
+		assert.Equal(t, d, ParseDirection(string(d.Arrow())))
+	}
+
+	_, ok := DirectionOf(image.Pt(1, 1))
+	assert.False(t, ok)
+}
+
+func TestParseDirection(t *testing.T) {
+	tests := map[string]Direction{
+		"U": DirUp, "N": DirUp, "↑": DirUp,
+		"R": DirRight, "E": DirRight, "→": DirRight,
+		"D": DirDown, "S": DirDown, "v": DirDown,
+		"L": DirLeft, "W": DirLeft, "<": DirLeft,
+	}
+	for s, want := range tests {
+		assert.Equal(t, want, ParseDirection(s), "direction %q", s)
+	}
+	assert.Panics(t, func() { ParseDirection("X") })
+}
+
+func TestMap2D_Neighbors(t *testing.T) {
+	m := NewMap2D("abc\ndef\nghi\n")
+
+	collect := func(it Iter2[image.Point, byte]) map[image.Point]byte {
+		m := make(map[image.Point]byte)
+		for pt, b := range it {
+			m[pt] = b
+		}
+		return m
+	}
+
+	assert.Equal(t, map[image.Point]byte{
+		{1, 0}: 'b',
+		{0, 1}: 'd',
+	}, collect(m.Neighbors(image.Pt(0, 0), CardinalDirections)))
+
+	assert.Equal(t, 8, len(collect(m.Neighbors(image.Pt(1, 1), AllDirections))))
+	assert.Equal(t, 3, len(collect(m.Neighbors(image.Pt(0, 0), AllDirections))))
+
+	assert.Equal(t, map[image.Point]byte{
+		{0, 2}: 'g',
+		{0, 1}: 'd',
+		{2, 0}: 'c',
+		{1, 0}: 'b',
+	}, collect(m.NeighborsWrapped(image.Pt(0, 0), CardinalDirections)))
+
+	assert.Equal(t, map[image.Point]byte{
+		{0, -1}: 'g',
+		{0, 1}:  'd',
+		{-1, 0}: 'c',
+		{1, 0}:  'b',
+	}, collect(m.NeighborsTiled(image.Pt(0, 0), CardinalDirections)))
+}
+
+func TestMap2D_Wrap(t *testing.T) {
+	m := NewMap2D("abc\ndef\n")
+	m.Bounds = m.Bounds.Add(image.Pt(-1, 1))
+
+	assert.Equal(t, image.Pt(-1, 1), m.Wrap(image.Pt(-1, 1)))
+	assert.Equal(t, image.Pt(1, 2), m.Wrap(image.Pt(-2, 0)))
+	assert.Equal(t, image.Pt(0, 1), m.Wrap(image.Pt(6, 5)))
+	assert.Equal(t, byte('f'), m.AtTiled(image.Pt(-2, 0)))
+}
+
+func TestMap2D_AllAround(t *testing.T) {
+	m := NewMap2D("abcd\nefgh\nijkl\n")
+
+	var got []byte
+	for _, b := range m.AllAround(image.Rect(1, 1, 3, 2)) {
+		got = append(got, b)
+	}
+	assert.Equal(t, "abcdehijkl", string(got))
+
+	got = got[:0]
+	for _, b := range m.AllAround(image.Rect(0, 0, 1, 1)) {
+		got = append(got, b)
+	}
+	assert.Equal(t, "bef", string(got))
+}
@@ -0,0 +1,211 @@
+package aocutil
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// DrawOptions are options for Map2D.DrawWith.
+type DrawOptions struct {
+	// Colors maps bytes to the colors of their cells, like the colorMap given
+	// to Map2D.Draw. Bytes that are not in the map are black.
+	Colors map[byte]color.RGBA
+	// Scale is the size of each cell in pixels. It defaults to 1.
+	Scale int
+	// GridLines draws a line of GridColor between cells. It needs a Scale of
+	// at least 3.
+	GridLines bool
+	GridColor color.RGBA
+	// Glyphs draws the byte of each cell as a character in GlyphColor. The
+	// font is 7x13 pixels, so cells smaller than that are clipped.
+	Glyphs     bool
+	GlyphColor color.RGBA
+	// Highlights are drawn over the cells in order. If a highlight has a
+	// Glyph, then it is drawn instead of the cell's byte.
+	Highlights []Highlight
+}
+
+var (
+	defaultGridColor  = color.RGBA{64, 64, 64, 255}
+	defaultGlyphColor = color.RGBA{255, 255, 255, 255}
+)
+
+// DrawWith draws the map to a paletted image with the given options. Unlike
+// Draw, the image bounds are scaled by opts.Scale.
+func (m Map2D) DrawWith(opts DrawOptions) *image.Paletted {
+	if opts.Scale < 1 {
+		opts.Scale = 1
+	}
+	if opts.GridColor == (color.RGBA{}) {
+		opts.GridColor = defaultGridColor
+	}
+	if opts.GlyphColor == (color.RGBA{}) {
+		opts.GlyphColor = defaultGlyphColor
+	}
+
+	img := ScaleImage(m.Draw(opts.Colors), opts.Scale).(*image.Paletted)
+	d := mapDrawer{img: img, scale: opts.Scale}
+
+	if opts.GridLines && opts.Scale >= 3 {
+		d.grid(m.Bounds, opts.GridColor)
+	}
+
+	glyphs := make(map[image.Point]string)
+	if opts.Glyphs {
+		for pt, v := range m.All() {
+			if v != 0 {
+				glyphs[pt] = string(v)
+			}
+		}
+	}
+
+	for _, hl := range opts.Highlights {
+		for _, pt := range hl.Points {
+			d.fillCell(pt, hl.Color, opts.GridLines)
+			if hl.Glyph != "" {
+				glyphs[pt] = hl.Glyph
+			}
+		}
+		if hl.Path {
+			for i := 1; i < len(hl.Points); i++ {
+				d.line(hl.Points[i-1], hl.Points[i], hl.Color)
+			}
+		}
+	}
+
+	if len(glyphs) > 0 {
+		glyphColor := d.colorIndex(opts.GlyphColor)
+		for pt, glyph := range glyphs {
+			d.glyph(pt, glyph, glyphColor)
+		}
+	}
+
+	return img
+}
+
+type mapDrawer struct {
+	img   *image.Paletted
+	scale int
+}
+
+// colorIndex returns the palette index of c, adding it to the palette if
+// needed.
+func (d *mapDrawer) colorIndex(c color.RGBA) uint8 {
+	for i, pc := range d.img.Palette {
+		if pc == color.Color(c) {
+			return uint8(i)
+		}
+	}
+	if len(d.img.Palette) < 256 {
+		d.img.Palette = append(d.img.Palette, c)
+		return uint8(len(d.img.Palette) - 1)
+	}
+	return uint8(d.img.Palette.Index(c))
+}
+
+// cellRect returns the pixels of the cell at pt.
+func (d *mapDrawer) cellRect(pt image.Point) image.Rectangle {
+	min := pt.Mul(d.scale)
+	return image.Rectangle{Min: min, Max: min.Add(image.Pt(d.scale, d.scale))}
+}
+
+func (d *mapDrawer) fill(r image.Rectangle, ix uint8) {
+	r = r.Intersect(d.img.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			d.img.SetColorIndex(x, y, ix)
+		}
+	}
+}
+
+func (d *mapDrawer) fillCell(pt image.Point, c color.RGBA, keepGrid bool) {
+	r := d.cellRect(pt)
+	if keepGrid && d.scale >= 3 {
+		// Keep the lines on the top and left edges of the cell, and the ones
+		// on the bottom and right edges of the map.
+		r.Min = r.Min.Add(image.Pt(1, 1))
+		r = r.Intersect(image.Rectangle{
+			Min: d.img.Rect.Min,
+			Max: d.img.Rect.Max.Sub(image.Pt(1, 1)),
+		})
+	}
+	d.fill(r, d.colorIndex(c))
+}
+
+// grid draws lines along the top and left edges of every cell, and along the
+// bottom and right edges of the map.
+func (d *mapDrawer) grid(bounds image.Rectangle, c color.RGBA) {
+	ix := d.colorIndex(c)
+	r := d.img.Rect
+	for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+		px := Min2(x*d.scale, r.Max.X-1)
+		d.fill(image.Rect(px, r.Min.Y, px+1, r.Max.Y), ix)
+	}
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		py := Min2(y*d.scale, r.Max.Y-1)
+		d.fill(image.Rect(r.Min.X, py, r.Max.X, py+1), ix)
+	}
+}
+
+// line draws a line between the centers of the cells at p and q.
+func (d *mapDrawer) line(p, q image.Point, c color.RGBA) {
+	ix := d.colorIndex(c)
+	width := Max2(d.scale/4, 1)
+	half := image.Pt(d.scale/2, d.scale/2)
+
+	p = p.Mul(d.scale).Add(half)
+	q = q.Mul(d.scale).Add(half)
+
+	dx, dy := Abs(q.X-p.X), -Abs(q.Y-p.Y)
+	sx, sy := sign(q.X-p.X), sign(q.Y-p.Y)
+	err := dx + dy
+	for {
+		min := p.Sub(image.Pt(width/2, width/2))
+		d.fill(image.Rectangle{Min: min, Max: min.Add(image.Pt(width, width))}, ix)
+		if p == q {
+			return
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			p.X += sx
+		} else {
+			err += dx
+			p.Y += sy
+		}
+	}
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// glyph draws s centered in the cell at pt.
+func (d *mapDrawer) glyph(pt image.Point, s string, ix uint8) {
+	face := basicfont.Face7x13
+	cell := d.cellRect(pt)
+
+	width := font.MeasureString(face, s).Ceil()
+	height := face.Metrics().Height.Ceil()
+	x := cell.Min.X + (d.scale-width)/2
+	y := cell.Min.Y + (d.scale-height)/2 + face.Metrics().Ascent.Ceil()
+
+	// Draw into the cell only, so that large glyphs are clipped to it.
+	drawer := font.Drawer{
+		Dst:  d.img.SubImage(cell).(*image.Paletted),
+		Src:  image.NewUniform(d.img.Palette[ix]),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(s)
+}
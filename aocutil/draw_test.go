@@ -0,0 +1,54 @@
+package aocutil
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestMap2D_DrawWith(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	m := NewMap2D("#.\n..\n")
+	img := m.DrawWith(DrawOptions{
+		Colors:    map[byte]color.RGBA{'#': red},
+		Scale:     4,
+		GridLines: true,
+		Highlights: []Highlight{
+			{Points: []image.Point{{1, 0}, {1, 1}}, Color: blue, Path: true},
+		},
+	})
+
+	at := func(x, y int) color.Color { return img.At(x, y) }
+
+	assert.Equal(t, image.Rect(0, 0, 8, 8), img.Bounds())
+	assert.Equal(t, color.Color(defaultGridColor), at(0, 0))
+	assert.Equal(t, color.Color(defaultGridColor), at(7, 7))
+	assert.Equal(t, color.Color(red), at(2, 2))
+	assert.Equal(t, color.Color(color.RGBA{0, 0, 0, 255}), at(2, 6))
+	assert.Equal(t, color.Color(blue), at(6, 2))
+	// The path crosses the grid line between the two highlighted cells.
+	assert.Equal(t, color.Color(blue), at(6, 4))
+}
+
+func TestMap2D_DrawWithGlyphs(t *testing.T) {
+	m := NewMap2D("#.\n")
+	img := m.DrawWith(DrawOptions{Scale: 16, Glyphs: true})
+
+	count := func(r image.Rectangle) int {
+		var n int
+		for pt := range PointsWithin(r) {
+			if img.At(pt.X, pt.Y) == color.Color(defaultGlyphColor) {
+				n++
+			}
+		}
+		return n
+	}
+
+	hash := count(image.Rect(0, 0, 16, 16))
+	dot := count(image.Rect(16, 0, 32, 16))
+	assert.True(t, hash > dot && dot > 0, "# has %d pixels and . has %d", hash, dot)
+}
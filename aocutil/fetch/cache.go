@@ -0,0 +1,102 @@
+package fetch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotCached is returned by Cache.Get if the input is not cached.
+var ErrNotCached = errors.New("input not cached")
+
+// Cache is an on-disk cache of puzzle inputs. Each input is stored alongside
+// its SHA-256 hash, which is checked on every read so that a truncated or
+// hand-edited input is never silently used.
+type Cache struct {
+	Dir string
+}
+
+// DefaultCache returns the cache in the user's cache directory.
+func DefaultCache() (*Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{Dir: filepath.Join(dir, "aoc")}, nil
+}
+
+func (c *Cache) path(year, day int, name string) string {
+	return filepath.Join(c.Dir, fmt.Sprint(year), fmt.Sprintf("%02d", day), name)
+}
+
+// Get returns the cached input of the given day. ErrNotCached is returned if
+// the input has not been cached yet.
+func (c *Cache) Get(year, day int) ([]byte, error) {
+	input, err := os.ReadFile(c.path(year, day, "input"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotCached
+		}
+		return nil, err
+	}
+
+	hash, err := os.ReadFile(c.path(year, day, "input.sha256"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// Put was interrupted before it wrote the hash.
+			return nil, ErrNotCached
+		}
+		return nil, fmt.Errorf("cannot read input hash: %w", err)
+	}
+
+	if got := Hash(input); got != strings.TrimSpace(string(hash)) {
+		return nil, fmt.Errorf("cached input of %d day %d is corrupted: hash %s, want %s",
+			year, day, got, hash)
+	}
+
+	return input, nil
+}
+
+// Put caches the input of the given day.
+func (c *Cache) Put(year, day int, input []byte) error {
+	inputPath := c.path(year, day, "input")
+	if err := os.MkdirAll(filepath.Dir(inputPath), 0o755); err != nil {
+		return err
+	}
+
+	// Write the hash last, so that an interrupted write leaves the input
+	// uncached instead of being trusted.
+	if err := writeFileAtomic(inputPath, input); err != nil {
+		return err
+	}
+	hash := []byte(Hash(input) + "\n")
+	return writeFileAtomic(c.path(year, day, "input.sha256"), hash)
+}
+
+// Hash returns the hex-encoded SHA-256 hash of the input.
+func Hash(input []byte) string {
+	h := sha256.Sum256(input)
+	return hex.EncodeToString(h[:])
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,183 @@
+// Package fetch downloads puzzle inputs and caches them on disk.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the default Advent of Code server.
+	DefaultBaseURL = "https://adventofcode.com"
+	// DefaultYear is the year of this repository.
+	DefaultYear = 2023
+	// DefaultMinInterval is the default minimum duration between requests.
+	DefaultMinInterval = 3 * time.Second
+	// UserAgent is sent with every request, as requested by the Advent of
+	// Code maintainers.
+	UserAgent = "libdb.so/aoc-2023/aocutil/fetch"
+)
+
+// Environment variables read by ClientFromEnv.
+const (
+	SessionEnv = "AOC_SESSION"
+	BaseURLEnv = "AOC_BASE_URL"
+)
+
+// ErrNoSession is returned if a request needs a session token but none is set.
+var ErrNoSession = errors.New("no session token, set $" + SessionEnv)
+
+// Client fetches puzzle inputs. It never requests an input that is already in
+// its cache, and it waits at least MinInterval between requests.
+type Client struct {
+	// BaseURL is the server to fetch from. It is overridable so that tests
+	// can use a local server.
+	BaseURL string
+	// Session is the value of the "session" cookie.
+	Session string
+	Year    int
+	Cache   *Cache
+	HTTP    *http.Client
+	// MinInterval is the minimum duration between two requests.
+	MinInterval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewClient creates a new client with the default settings.
+func NewClient(session string, cache *Cache) *Client {
+	return &Client{
+		BaseURL:     DefaultBaseURL,
+		Session:     session,
+		Year:        DefaultYear,
+		Cache:       cache,
+		HTTP:        http.DefaultClient,
+		MinInterval: DefaultMinInterval,
+	}
+}
+
+// ClientFromEnv creates a new client using the default cache and the session
+// token and base URL from the environment.
+func ClientFromEnv() (*Client, error) {
+	cache, err := DefaultCache()
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewClient(os.Getenv(SessionEnv), cache)
+	if baseURL := os.Getenv(BaseURLEnv); baseURL != "" {
+		c.BaseURL = baseURL
+	}
+	return c, nil
+}
+
+// Input returns the input of the given day, fetching it only if it is not
+// cached yet.
+func (c *Client) Input(ctx context.Context, day int) ([]byte, error) {
+	if c.Cache != nil {
+		input, err := c.Cache.Get(c.Year, day)
+		if err == nil {
+			return input, nil
+		}
+		if !errors.Is(err, ErrNotCached) {
+			return nil, err
+		}
+	}
+
+	input, err := c.fetchInput(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Cache != nil {
+		if err := c.Cache.Put(c.Year, day, input); err != nil {
+			return nil, fmt.Errorf("cannot cache input: %w", err)
+		}
+	}
+
+	return input, nil
+}
+
+func (c *Client) fetchInput(ctx context.Context, day int) ([]byte, error) {
+	url := fmt.Sprintf("%s/%d/day/%d/input", strings.TrimSuffix(c.BaseURL, "/"), c.Year, day)
+
+	req, err := c.NewRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read input of day %d: %w", day, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch input of day %d: %s: %s",
+			day, resp.Status, firstLine(body))
+	}
+
+	return body, nil
+}
+
+// NewRequest creates a new request with the session cookie and user agent set.
+func (c *Client) NewRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	if c.Session == "" {
+		return nil, ErrNoSession
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	return req, nil
+}
+
+// Do sends the request after waiting for the rate limit.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if err := c.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return c.HTTP.Do(req)
+}
+
+// wait blocks until MinInterval has passed since the last request.
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d := c.MinInterval - time.Since(c.last); !c.last.IsZero() && d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	c.last = time.Now()
+	return nil
+}
+
+func firstLine(b []byte) string {
+	line, _, _ := bytes.Cut(bytes.TrimSpace(b), []byte("\n"))
+	return string(line)
+}
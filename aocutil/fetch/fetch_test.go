@@ -0,0 +1,118 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func newTestServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "hunter2" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/2023/day/1/input":
+			w.Write([]byte("1abc2\npqr3stu8vwx\n"))
+		case "/2023/day/2/input":
+			w.Write([]byte("Game 1: 3 blue\n"))
+		default:
+			http.Error(w, "Please don't repeatedly request this endpoint before it unlocks!", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T, srv *httptest.Server) *Client {
+	c := NewClient("hunter2", &Cache{Dir: t.TempDir()})
+	c.BaseURL = srv.URL
+	c.MinInterval = 0
+	return c
+}
+
+func TestClientInput(t *testing.T) {
+	var requests atomic.Int32
+	srv := newTestServer(t, &requests)
+	c := newTestClient(t, srv)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		input, err := c.Input(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "1abc2\npqr3stu8vwx\n", string(input))
+	}
+	assert.Equal(t, int32(1), requests.Load(), "cached input must not be fetched again")
+
+	_, err := c.Input(ctx, 25)
+	assert.EqualError(t, err, "cannot fetch input of day 25: 404 Not Found: "+
+		"Please don't repeatedly request this endpoint before it unlocks!")
+
+	c.Session = "wrong"
+	_, err = c.Input(ctx, 2)
+	assert.Error(t, err)
+
+	c.Session = ""
+	_, err = c.Input(ctx, 2)
+	assert.IsError(t, err, ErrNoSession)
+
+	// Cached inputs do not need a session at all.
+	_, err = c.Input(ctx, 1)
+	assert.NoError(t, err)
+}
+
+func TestClientRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	srv := newTestServer(t, &requests)
+	c := newTestClient(t, srv)
+	c.Cache = nil
+	c.MinInterval = 50 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.Input(context.Background(), 1)
+		assert.NoError(t, err)
+	}
+	assert.True(t, time.Since(start) >= 2*c.MinInterval, "requests were not rate-limited")
+
+	c.MinInterval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.Input(ctx, 1)
+	assert.IsError(t, err, context.Canceled)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestCacheCorruption(t *testing.T) {
+	cache := &Cache{Dir: t.TempDir()}
+
+	_, err := cache.Get(2023, 1)
+	assert.IsError(t, err, ErrNotCached)
+
+	assert.NoError(t, cache.Put(2023, 1, []byte("hello\n")))
+	input, err := cache.Get(2023, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(input))
+
+	path := filepath.Join(cache.Dir, "2023", "01", "input")
+	assert.NoError(t, os.WriteFile(path, []byte("hell\n"), 0o644))
+	_, err = cache.Get(2023, 1)
+	assert.Error(t, err)
+
+	// An input without a hash was never completely cached.
+	assert.NoError(t, os.Remove(path+".sha256"))
+	_, err = cache.Get(2023, 1)
+	assert.IsError(t, err, ErrNotCached)
+}
@@ -0,0 +1,352 @@
+package graph
+
+import (
+	"context"
+	"slices"
+
+	"libdb.so/aoc-2023/aocutil"
+)
+
+// Adjacency is a directed graph given as the nodes that each node has edges
+// to. Nodes that only appear as targets are part of the graph too. Unless
+// noted otherwise, the order of nodes returned by its functions is
+// unspecified, since maps are unordered.
+type Adjacency[K comparable] map[K][]K
+
+// Nodes returns every node in the graph, including the ones that only appear
+// as targets.
+func (g Adjacency[K]) Nodes() []K {
+	seen := aocutil.NewSet[K](len(g))
+	var nodes []K
+	add := func(n K) {
+		if seen.Add(n) {
+			nodes = append(nodes, n)
+		}
+	}
+	for from, tos := range g {
+		add(from)
+		for _, to := range tos {
+			add(to)
+		}
+	}
+	return nodes
+}
+
+// Reverse returns the graph with every edge reversed.
+func (g Adjacency[K]) Reverse() Adjacency[K] {
+	r := make(Adjacency[K], len(g))
+	for from, tos := range g {
+		for _, to := range tos {
+			r[to] = append(r[to], from)
+		}
+	}
+	return r
+}
+
+// Undirected returns the graph with every edge in both directions.
+func (g Adjacency[K]) Undirected() Adjacency[K] {
+	u := make(Adjacency[K], len(g))
+	for from, tos := range g {
+		for _, to := range tos {
+			u[from] = append(u[from], to)
+			u[to] = append(u[to], from)
+		}
+	}
+	return u
+}
+
+// StronglyConnectedComponents returns the strongly connected components of
+// the graph: the largest sets of nodes where every node can reach every
+// other. Components are returned in reverse topological order, so a component
+// only has edges to components before it.
+func StronglyConnectedComponents[K comparable](g Adjacency[K]) [][]K {
+	// This is Tarjan's algorithm.
+	type nodeState struct {
+		index   int
+		lowlink int
+		onStack bool
+	}
+
+	states := make(map[K]*nodeState)
+	var stack []K
+	var components [][]K
+
+	var connect func(n K)
+	connect = func(n K) {
+		s := &nodeState{index: len(states), lowlink: len(states), onStack: true}
+		states[n] = s
+		stack = append(stack, n)
+
+		for _, to := range g[n] {
+			if ts, ok := states[to]; !ok {
+				connect(to)
+				s.lowlink = min(s.lowlink, states[to].lowlink)
+			} else if ts.onStack {
+				s.lowlink = min(s.lowlink, ts.index)
+			}
+		}
+
+		if s.lowlink == s.index {
+			var component []K
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				states[top].onStack = false
+				component = append(component, top)
+				if top == n {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, n := range g.Nodes() {
+		if _, ok := states[n]; !ok {
+			connect(n)
+		}
+	}
+
+	return components
+}
+
+// TopologicalSort returns the nodes in an order where every node comes before
+// the nodes it has edges to. It returns false if the graph has a cycle.
+func TopologicalSort[K comparable](g Adjacency[K]) ([]K, bool) {
+	// This is Kahn's algorithm.
+	nodes := g.Nodes()
+	indegree := make(map[K]int, len(nodes))
+	for _, tos := range g {
+		for _, to := range tos {
+			indegree[to]++
+		}
+	}
+
+	var queue []K
+	for _, n := range nodes {
+		if indegree[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+
+	order := make([]K, 0, len(nodes))
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		order = append(order, n)
+
+		for _, to := range g[n] {
+			if indegree[to]--; indegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	if len(order) != len(nodes) {
+		return nil, false
+	}
+	return order, true
+}
+
+// FindCycle returns the nodes of a cycle in the graph, in the order they are
+// visited, with the first node having an edge from the last one. It returns
+// nil if the graph has no cycles.
+func FindCycle[K comparable](g Adjacency[K]) []K {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	colors := make(map[K]int)
+	var path []K
+
+	var visit func(n K) []K
+	visit = func(n K) []K {
+		colors[n] = visiting
+		path = append(path, n)
+
+		for _, to := range g[n] {
+			switch colors[to] {
+			case visiting:
+				// to is on the path, so the path from it to n is a cycle.
+				i := slices.Index(path, to)
+				return slices.Clone(path[i:])
+			case unvisited:
+				if cycle := visit(to); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		colors[n] = visited
+		path = path[:len(path)-1]
+		return nil
+	}
+
+	for _, n := range g.Nodes() {
+		if colors[n] == unvisited {
+			if cycle := visit(n); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// ArticulationPoints returns the nodes that disconnect the graph when they are
+// removed, ignoring the direction of edges.
+func ArticulationPoints[K comparable](g Adjacency[K]) []K {
+	u := g.Undirected()
+
+	type nodeState struct {
+		index   int
+		lowlink int
+	}
+
+	states := make(map[K]*nodeState)
+	var points []K
+
+	var visit func(n, parent K, isRoot bool)
+	visit = func(n, parent K, isRoot bool) {
+		s := &nodeState{index: len(states), lowlink: len(states)}
+		states[n] = s
+
+		var children int
+		var isPoint bool
+		skippedParent := false
+		for _, to := range u[n] {
+			if !isRoot && to == parent && !skippedParent {
+				// Only skip one edge to the parent, so that parallel edges
+				// still count as another way back.
+				skippedParent = true
+				continue
+			}
+			if ts, ok := states[to]; ok {
+				s.lowlink = min(s.lowlink, ts.index)
+				continue
+			}
+
+			children++
+			visit(to, n, false)
+			s.lowlink = min(s.lowlink, states[to].lowlink)
+			if !isRoot && states[to].lowlink >= s.index {
+				isPoint = true
+			}
+		}
+
+		if isRoot && children > 1 || isPoint {
+			points = append(points, n)
+		}
+	}
+
+	for _, n := range u.Nodes() {
+		if _, ok := states[n]; !ok {
+			visit(n, n, true)
+		}
+	}
+
+	return points
+}
+
+// MinCut returns the global minimum cut of the graph, ignoring the direction
+// of edges: the fewest edges that need to be removed to split the graph in
+// two. Parallel edges each count. It also returns the nodes on one side of
+// the cut. The graph must be connected and have at least 2 nodes.
+//
+// It uses the Stoer-Wagner algorithm, which takes O(V³) time.
+func MinCut[K comparable](g Adjacency[K]) (cut int, side []K) {
+	return MinCutContext(context.Background(), g)
+}
+
+// MinCutContext is like MinCut, except it stops once ctx is done and returns
+// the smallest cut found so far, which may not be the minimum. The cut is -1
+// if none was found yet.
+func MinCutContext[K comparable](ctx context.Context, g Adjacency[K]) (cut int, side []K) {
+	nodes := g.Nodes()
+	aocutil.Assertf(len(nodes) >= 2, "graph: MinCut needs at least 2 nodes, got %d", len(nodes))
+
+	index := make(map[K]int, len(nodes))
+	for i, n := range nodes {
+		index[n] = i
+	}
+
+	weights := make([][]int, len(nodes))
+	for i := range weights {
+		weights[i] = make([]int, len(nodes))
+	}
+	for from, tos := range g {
+		for _, to := range tos {
+			i, j := index[from], index[to]
+			if i != j {
+				weights[i][j]++
+				weights[j][i]++
+			}
+		}
+	}
+
+	// merged[i] holds the original nodes that were merged into node i.
+	merged := make([][]int, len(nodes))
+	for i := range merged {
+		merged[i] = []int{i}
+	}
+	// active holds the nodes that have not been merged into another.
+	active := make([]int, len(nodes))
+	for i := range active {
+		active[i] = i
+	}
+
+	best := -1
+	var bestSide []int
+	connectivity := make([]int, len(nodes))
+	added := make([]bool, len(nodes))
+
+	// Each phase takes O(V²) time, so checking ctx once per phase is cheap.
+	for len(active) > 1 && ctx.Err() == nil {
+
+		// Find the most tightly connected order of the active nodes. The
+		// last two, s and t, are merged, and the cut between t and the rest
+		// is a candidate.
+		for _, v := range active {
+			connectivity[v] = 0
+			added[v] = false
+		}
+
+		var s, t int = -1, -1
+		for range active {
+			next := -1
+			for _, v := range active {
+				if !added[v] && (next == -1 || connectivity[v] > connectivity[next]) {
+					next = v
+				}
+			}
+			added[next] = true
+			s, t = t, next
+			for _, v := range active {
+				if !added[v] {
+					connectivity[v] += weights[next][v]
+				}
+			}
+		}
+
+		if best == -1 || connectivity[t] < best {
+			best = connectivity[t]
+			bestSide = slices.Clone(merged[t])
+		}
+
+		// Merge t into s.
+		merged[s] = append(merged[s], merged[t]...)
+		for _, v := range active {
+			weights[s][v] += weights[t][v]
+			weights[v][s] = weights[s][v]
+		}
+		weights[s][s] = 0
+		active = slices.DeleteFunc(active, func(v int) bool { return v == t })
+	}
+
+	side = make([]K, len(bestSide))
+	for i, v := range bestSide {
+		side[i] = nodes[v]
+	}
+	return best, side
+}
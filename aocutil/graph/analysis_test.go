@@ -0,0 +1,153 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+// parseAdjacency parses lines of "from: to to ...".
+func parseAdjacency(s string) Adjacency[string] {
+	g := make(Adjacency[string])
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		from, tos, _ := strings.Cut(line, ":")
+		g[from] = append(g[from], strings.Fields(tos)...)
+	}
+	return g
+}
+
+func sorted(s []string) []string {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return s
+}
+
+func TestAdjacency(t *testing.T) {
+	g := parseAdjacency(`
+a: b c
+b: c
+`)
+	assert.Equal(t, []string{"a", "b", "c"}, sorted(g.Nodes()))
+	assert.Equal(t, []string{"a", "b"}, sorted(g.Reverse()["c"]))
+	assert.Equal(t, []string{"a", "c"}, sorted(g.Undirected()["b"]))
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := parseAdjacency(`
+a: b
+b: c
+c: a d
+d: e
+e: d f
+f:
+`)
+	components := StronglyConnectedComponents(g)
+
+	var got []string
+	for _, c := range components {
+		got = append(got, strings.Join(sorted(c), ""))
+	}
+	// Reverse topological order: f before de before abc.
+	assert.Equal(t, []string{"f", "de", "abc"}, got)
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := parseAdjacency(`
+shirt: tie belt
+tie: jacket
+pants: shoes belt
+belt: jacket
+socks: shoes
+`)
+	order, ok := TopologicalSort(g)
+	assert.True(t, ok)
+	assert.Equal(t, len(g.Nodes()), len(order))
+
+	pos := make(map[string]int)
+	for i, n := range order {
+		pos[n] = i
+	}
+	for from, tos := range g {
+		for _, to := range tos {
+			assert.True(t, pos[from] < pos[to], "%s before %s", from, to)
+		}
+	}
+
+	g["jacket"] = []string{"shirt"}
+	_, ok = TopologicalSort(g)
+	assert.False(t, ok)
+}
+
+func TestFindCycle(t *testing.T) {
+	g := parseAdjacency(`
+a: b
+b: c d
+d: e
+e: b
+`)
+	cycle := FindCycle(g)
+	assert.Equal(t, []string{"b", "d", "e"}, sorted(cycle))
+	for i, n := range cycle {
+		next := cycle[(i+1)%len(cycle)]
+		assert.True(t, slices.Contains(g[n], next), "%s -> %s", n, next)
+	}
+
+	delete(g, "e")
+	assert.Equal(t, []string(nil), FindCycle(g))
+}
+
+func TestArticulationPoints(t *testing.T) {
+	// Two triangles joined by c-d, with a tail from d to g.
+	g := parseAdjacency(`
+a: b c
+b: c
+c: d
+d: e f
+e: f
+f: g
+`)
+	assert.Equal(t, []string{"c", "d", "f"}, sorted(ArticulationPoints(g)))
+
+	// Parallel edges are another way around.
+	g = parseAdjacency(`
+a: b b
+b: c
+`)
+	assert.Equal(t, []string{"b"}, sorted(ArticulationPoints(g)))
+}
+
+func TestMinCut(t *testing.T) {
+	// The example from day 25 of 2023, which is split by cutting 3 wires.
+	g := parseAdjacency(`
+jqt: rhn xhk nvd
+rsh: frs pzl lsr
+xhk: hfx
+cmg: qnr nvd lhk bvb
+rhn: xhk bvb hfx
+bvb: xhk hfx
+pzl: lsr hfx nvd
+qnr: nvd
+ntq: jqt hfx bvb xhk
+nvd: lhk
+lsr: lhk
+rzs: qnr cmg lsr rsh
+frs: qnr lhk lsr
+`)
+	cut, side := MinCut(g)
+	assert.Equal(t, 3, cut)
+
+	n := len(g.Nodes())
+	assert.Equal(t, 54, len(side)*(n-len(side)))
+}
+
+func TestMinCutContext_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cut, side := MinCutContext(ctx, parseAdjacency("a: b c\nb: c"))
+	assert.Equal(t, -1, cut)
+	assert.Equal(t, 0, len(side))
+}
@@ -0,0 +1,244 @@
+// Package graph finds paths in graphs.
+//
+// Shortest paths are found over implicit graphs. Such a graph is given by a
+// NeighborsFunc that yields the states reachable from a state along with the
+// cost of getting there, so states never have to be enumerated upfront. Any
+// comparable type can be a state, e.g.
+//
+//	type state struct {
+//		pt       image.Point
+//		dir      aocutil.Direction
+//		straight int
+//	}
+//
+// Longest simple paths are found over Junctions, which are made by contracting
+// the corridors of a maze with Compress.
+//
+// Explicit graphs given as an Adjacency can be analyzed for their structure:
+// strongly connected components, topological order, cycles, articulation
+// points and minimum cuts.
+package graph
+
+import (
+	"context"
+	"slices"
+
+	"libdb.so/aoc-2023/aocutil"
+)
+
+// NeighborsFunc returns an iterator over the states next to s and the cost of
+// moving to each of them. Costs must not be negative.
+type NeighborsFunc[S comparable] func(s S) aocutil.Iter2[S, int]
+
+// Result is the result of a search.
+type Result[S comparable] struct {
+	// Found is true if a goal state was reached.
+	Found bool
+	// Goal is the goal state that was reached.
+	Goal S
+	// Cost is the cost of the cheapest path to Goal.
+	Cost int
+	// Dist maps every state that was reached to the cost of the cheapest
+	// known path to it. States are only final if they were reached before
+	// the goal.
+	Dist map[S]int
+	// Prev maps every state that was reached, except for the start states,
+	// to the state before it on the cheapest known path.
+	Prev map[S]S
+}
+
+// Path returns the states on the cheapest path to the goal, starting with a
+// start state and ending with the goal. It returns nil if no goal was found.
+func (r Result[S]) Path() []S {
+	if !r.Found {
+		return nil
+	}
+	return r.PathTo(r.Goal)
+}
+
+// PathTo returns the states on the cheapest known path to s, starting with a
+// start state and ending with s. It returns nil if s was never reached.
+func (r Result[S]) PathTo(s S) []S {
+	if _, ok := r.Dist[s]; !ok {
+		return nil
+	}
+
+	path := []S{s}
+	for {
+		prev, ok := r.Prev[s]
+		if !ok {
+			break
+		}
+		path = append(path, prev)
+		s = prev
+	}
+
+	slices.Reverse(path)
+	return path
+}
+
+func newResult[S comparable]() Result[S] {
+	return Result[S]{
+		Dist: make(map[S]int),
+		Prev: make(map[S]S),
+	}
+}
+
+// Dijkstra finds the cheapest path from any of the start states to a state
+// for which isGoal returns true. If isGoal is nil, then every reachable state
+// is visited, and Dist holds the cost of the cheapest path to each of them.
+func Dijkstra[S comparable](starts []S, neighbors NeighborsFunc[S], isGoal func(S) bool) Result[S] {
+	return AStarContext(context.Background(), starts, neighbors, isGoal, nil)
+}
+
+// DijkstraContext is like Dijkstra, except it stops searching once ctx is
+// done. The result is then incomplete, and Found is false.
+func DijkstraContext[S comparable](ctx context.Context, starts []S, neighbors NeighborsFunc[S], isGoal func(S) bool) Result[S] {
+	return AStarContext(ctx, starts, neighbors, isGoal, nil)
+}
+
+type queueItem[S comparable] struct {
+	state    S
+	cost     int
+	priority int
+}
+
+// AStar is like Dijkstra, except states are visited in the order of their
+// cost plus the estimated cost from them to the goal given by heuristic. The
+// heuristic must never overestimate the cost, or a more expensive path may be
+// returned. A nil heuristic makes AStar the same as Dijkstra.
+func AStar[S comparable](starts []S, neighbors NeighborsFunc[S], isGoal func(S) bool, heuristic func(S) int) Result[S] {
+	return AStarContext(context.Background(), starts, neighbors, isGoal, heuristic)
+}
+
+// AStarContext is like AStar, except it stops searching once ctx is done, as
+// with DijkstraContext.
+func AStarContext[S comparable](ctx context.Context, starts []S, neighbors NeighborsFunc[S], isGoal func(S) bool, heuristic func(S) int) Result[S] {
+	if heuristic == nil {
+		heuristic = func(S) int { return 0 }
+	}
+
+	r := newResult[S]()
+	queue := aocutil.NewHeap(aocutil.MinHeap, 0, func(a, b queueItem[S]) int {
+		return aocutil.CompareOrdered(a.priority, b.priority)
+	})
+
+	for _, s := range starts {
+		r.Dist[s] = 0
+		queue.Push(queueItem[S]{s, 0, heuristic(s)})
+	}
+
+	var i int
+	for queue.Len() > 0 {
+		if aocutil.Canceled(ctx, &i) {
+			break
+		}
+
+		item := queue.Pop()
+		if item.cost > r.Dist[item.state] {
+			// A cheaper path to this state was found after it was queued.
+			continue
+		}
+
+		if isGoal != nil && isGoal(item.state) {
+			r.Found = true
+			r.Goal = item.state
+			r.Cost = item.cost
+			return r
+		}
+
+		for next, cost := range neighbors(item.state) {
+			aocutil.Assertf(cost >= 0, "graph: negative cost %d", cost)
+
+			cost += item.cost
+			if known, ok := r.Dist[next]; ok && known <= cost {
+				continue
+			}
+
+			r.Dist[next] = cost
+			r.Prev[next] = item.state
+			queue.Push(queueItem[S]{next, cost, cost + heuristic(next)})
+		}
+	}
+
+	return r
+}
+
+// BFS01 is like Dijkstra for graphs where every cost is either 0 or 1. It
+// uses a double-ended queue instead of a heap, which makes it faster. It
+// panics if a cost is not 0 or 1.
+func BFS01[S comparable](starts []S, neighbors NeighborsFunc[S], isGoal func(S) bool) Result[S] {
+	return BFS01Context(context.Background(), starts, neighbors, isGoal)
+}
+
+// BFS01Context is like BFS01, except it stops searching once ctx is done, as
+// with DijkstraContext.
+func BFS01Context[S comparable](ctx context.Context, starts []S, neighbors NeighborsFunc[S], isGoal func(S) bool) Result[S] {
+	r := newResult[S]()
+
+	// The deque is split into a stack for its front and a queue for its back.
+	// States reached with a cost of 0 are pushed to the front and states
+	// reached with a cost of 1 to the back, so the deque stays sorted by cost.
+	// The queue is consumed from head, and its backing array is reused once
+	// every item in it has been consumed.
+	type dequeItem struct {
+		state S
+		cost  int
+	}
+	var front, deque []dequeItem
+	var head int
+
+	for _, s := range starts {
+		r.Dist[s] = 0
+		deque = append(deque, dequeItem{s, 0})
+	}
+
+	var i int
+	for len(front) > 0 || head < len(deque) {
+		if aocutil.Canceled(ctx, &i) {
+			break
+		}
+
+		var item dequeItem
+		if len(front) > 0 {
+			item = front[len(front)-1]
+			front = front[:len(front)-1]
+		} else {
+			item = deque[head]
+			head++
+			if head == len(deque) {
+				deque, head = deque[:0], 0
+			}
+		}
+
+		if item.cost > r.Dist[item.state] {
+			continue
+		}
+
+		if isGoal != nil && isGoal(item.state) {
+			r.Found = true
+			r.Goal = item.state
+			r.Cost = item.cost
+			return r
+		}
+
+		for next, cost := range neighbors(item.state) {
+			aocutil.Assertf(cost == 0 || cost == 1, "graph: BFS01 cost %d is not 0 or 1", cost)
+
+			nextCost := item.cost + cost
+			if known, ok := r.Dist[next]; ok && known <= nextCost {
+				continue
+			}
+
+			r.Dist[next] = nextCost
+			r.Prev[next] = item.state
+			if cost == 0 {
+				front = append(front, dequeItem{next, nextCost})
+			} else {
+				deque = append(deque, dequeItem{next, nextCost})
+			}
+		}
+	}
+
+	return r
+}
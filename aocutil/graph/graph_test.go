@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"libdb.so/aoc-2023/aocutil"
+)
+
+// testMap is a maze where each digit is the cost of entering the cell and '#'
+// is a wall.
+var testMap = aocutil.NewMap2D(`
+1163751
+1381373
+21#6511
+36#4911
+7463417
+1319128
+1359912
+`)
+
+func mapNeighbors(pt image.Point) aocutil.Iter2[image.Point, int] {
+	return func(yield func(image.Point, int) bool) {
+		for next, b := range testMap.Neighbors(pt, aocutil.CardinalDirections) {
+			if b == '#' {
+				continue
+			}
+			if !yield(next, int(b-'0')) {
+				return
+			}
+		}
+	}
+}
+
+var (
+	testStart = testMap.Bounds.Min
+	testEnd   = testMap.Bounds.Max.Sub(image.Pt(1, 1))
+)
+
+func isTestEnd(pt image.Point) bool { return pt == testEnd }
+
+func pathCost(path []image.Point) int {
+	var cost int
+	for _, pt := range path[1:] {
+		cost += int(testMap.At(pt) - '0')
+	}
+	return cost
+}
+
+func TestDijkstra(t *testing.T) {
+	r := Dijkstra([]image.Point{testStart}, mapNeighbors, isTestEnd)
+	assert.True(t, r.Found)
+	assert.Equal(t, testEnd, r.Goal)
+	assert.Equal(t, 27, r.Cost)
+
+	path := r.Path()
+	assert.Equal(t, testStart, path[0])
+	assert.Equal(t, testEnd, path[len(path)-1])
+	assert.Equal(t, r.Cost, pathCost(path))
+}
+
+func TestDijkstra_all(t *testing.T) {
+	r := Dijkstra([]image.Point{testStart}, mapNeighbors, nil)
+	assert.False(t, r.Found)
+	assert.Equal(t, []image.Point(nil), r.Path())
+	assert.Equal(t, 27, r.Dist[testEnd])
+	assert.Equal(t, 47, len(r.Dist)) // every cell but the 2 walls
+	assert.Equal(t, []image.Point{testStart}, r.PathTo(testStart))
+}
+
+func TestDijkstra_notFound(t *testing.T) {
+	r := Dijkstra([]image.Point{testStart}, mapNeighbors, func(pt image.Point) bool {
+		return pt == image.Pt(2, 2) // a wall
+	})
+	assert.False(t, r.Found)
+}
+
+func TestDijkstraContext_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The plane is infinite, so the search only stops because of ctx.
+	plane := func(pt image.Point) aocutil.Iter2[image.Point, int] {
+		return func(yield func(image.Point, int) bool) {
+			for _, dir := range aocutil.CardinalDirections {
+				if !yield(pt.Add(dir), 1) {
+					return
+				}
+			}
+		}
+	}
+	r := DijkstraContext(ctx, []image.Point{{}}, plane, func(image.Point) bool { return false })
+	assert.False(t, r.Found)
+}
+
+func TestAStar(t *testing.T) {
+	var visited int
+	neighbors := func(pt image.Point) aocutil.Iter2[image.Point, int] {
+		visited++
+		return mapNeighbors(pt)
+	}
+	manhattan := func(pt image.Point) int {
+		d := testEnd.Sub(pt)
+		return aocutil.Abs(d.X) + aocutil.Abs(d.Y)
+	}
+
+	r := AStar([]image.Point{testStart}, neighbors, isTestEnd, manhattan)
+	assert.True(t, r.Found)
+	assert.Equal(t, 27, r.Cost)
+	assert.Equal(t, r.Cost, pathCost(r.Path()))
+	assert.True(t, visited < 47, "A* visited %d states", visited)
+}
+
+func TestBFS01(t *testing.T) {
+	// Moving onto a '#' costs 1, anything else is free.
+	m := aocutil.NewMap2D(`
+..#..
+.##.#
+..#..
+`)
+	neighbors := func(pt image.Point) aocutil.Iter2[image.Point, int] {
+		return func(yield func(image.Point, int) bool) {
+			for next, b := range m.Neighbors(pt, aocutil.CardinalDirections) {
+				cost := 0
+				if b == '#' {
+					cost = 1
+				}
+				if !yield(next, cost) {
+					return
+				}
+			}
+		}
+	}
+
+	end := image.Pt(4, 0)
+	r := BFS01([]image.Point{{0, 0}}, neighbors, func(pt image.Point) bool { return pt == end })
+	assert.True(t, r.Found)
+	assert.Equal(t, 1, r.Cost)
+
+	want := Dijkstra([]image.Point{{0, 0}}, neighbors, nil)
+	got := BFS01([]image.Point{{0, 0}}, neighbors, nil)
+	assert.Equal(t, want.Dist, got.Dist)
+}
+
+func TestBFS01_invalidCost(t *testing.T) {
+	assert.Panics(t, func() {
+		BFS01([]image.Point{testStart}, mapNeighbors, nil)
+	})
+}
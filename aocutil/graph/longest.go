@@ -0,0 +1,403 @@
+package graph
+
+import (
+	"context"
+	"image"
+	"runtime"
+	"sync"
+
+	"libdb.so/aoc-2023/aocutil"
+	"libdb.so/aoc-2023/aocutil/graph/viz"
+)
+
+// Junctions is a weighted graph of the junctions of a maze. Junctions are
+// referred to by their index in Points. It is made by Compress, which
+// contracts the corridors between junctions into single edges.
+type Junctions struct {
+	// Points is the point of each junction.
+	Points []image.Point
+	// Edges holds the edges leaving each junction.
+	Edges [][]Edge
+
+	index map[image.Point]int
+}
+
+// Edge is an edge between two junctions.
+type Edge struct {
+	To     int
+	Weight int
+}
+
+// Index returns the index of the junction at pt.
+func (g *Junctions) Index(pt image.Point) (int, bool) {
+	i, ok := g.index[pt]
+	return i, ok
+}
+
+// Len returns the number of junctions.
+func (g *Junctions) Len() int {
+	return len(g.Points)
+}
+
+// Graph returns the junctions as a directed graph for visualization, with
+// edges weighted by their length. Nodes are named by name, or by their point
+// if name is nil.
+func (g *Junctions) Graph(name func(image.Point) string) *viz.Graph {
+	if name == nil {
+		name = image.Point.String
+	}
+
+	v := viz.New(true)
+	for _, pt := range g.Points {
+		v.AddNode(name(pt))
+	}
+	for from, edges := range g.Edges {
+		for _, e := range edges {
+			v.AddEdge(name(g.Points[from]), name(g.Points[e.To])).Weight = e.Weight
+		}
+	}
+	return v
+}
+
+func (g *Junctions) add(pt image.Point) (i int, added bool) {
+	if i, ok := g.index[pt]; ok {
+		return i, false
+	}
+	g.Points = append(g.Points, pt)
+	g.Edges = append(g.Edges, nil)
+	g.index[pt] = len(g.Points) - 1
+	return len(g.Points) - 1, true
+}
+
+// Compress walks the maze from start and contracts every corridor into a
+// single edge weighted by its length. A point is a junction if it is the
+// start or if it can be left in any number of ways other than one, not
+// counting the way back. Dead ends, such as the exit of a maze, are junctions
+// too.
+//
+// The neighbors function gives the points that can be moved to from a point.
+// It may be directional, e.g. for slopes that can only be walked down, in
+// which case the edges are directional too.
+func Compress(start image.Point, neighbors func(image.Point) aocutil.Iter[image.Point]) *Junctions {
+	return CompressContext(context.Background(), start, neighbors)
+}
+
+// CompressContext is like Compress, except it stops walking once ctx is done
+// and returns the junctions found so far.
+func CompressContext(ctx context.Context, start image.Point, neighbors func(image.Point) aocutil.Iter[image.Point]) *Junctions {
+	g := &Junctions{index: make(map[image.Point]int)}
+	g.add(start)
+
+	var n int
+	queue := []int{0}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		origin := g.Points[from]
+
+		for pt := range neighbors(origin) {
+			prev, cur, steps := origin, pt, 1
+			for {
+				if aocutil.Canceled(ctx, &n) {
+					return g
+				}
+
+				if _, ok := g.index[cur]; ok {
+					break
+				}
+				next, ok := onlyNeighbor(neighbors(cur), prev)
+				if !ok {
+					break
+				}
+				prev, cur = cur, next
+				steps++
+			}
+
+			to, added := g.add(cur)
+			if added {
+				queue = append(queue, to)
+			}
+			g.Edges[from] = append(g.Edges[from], Edge{To: to, Weight: steps})
+		}
+	}
+
+	return g
+}
+
+// onlyNeighbor returns the only neighbor that is not prev. It returns false
+// if there are none or more than one.
+func onlyNeighbor(neighbors aocutil.Iter[image.Point], prev image.Point) (image.Point, bool) {
+	var only image.Point
+	var count int
+	for pt := range neighbors {
+		if pt == prev {
+			continue
+		}
+		if count++; count > 1 {
+			return image.Point{}, false
+		}
+		only = pt
+	}
+	return only, count == 1
+}
+
+// CompressMap is like Compress for mazes where every cell that passable
+// returns true for can be moved to from its 4 neighbors.
+func CompressMap(m aocutil.Map2D, start image.Point, passable func(byte) bool) *Junctions {
+	return Compress(start, func(pt image.Point) aocutil.Iter[image.Point] {
+		return func(yield func(image.Point) bool) {
+			for next, b := range m.Neighbors(pt, aocutil.CardinalDirections) {
+				if passable(b) && !yield(next) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// LongestPathOptions are options for Junctions.LongestPath.
+type LongestPathOptions struct {
+	// Memoize caches the longest distance to the target from every junction
+	// for every set of visited junctions. It only pays off if many paths reach
+	// the same junction through the same set of junctions; on grid-like mazes,
+	// filling the cache usually costs more than it saves. It is ignored for
+	// graphs of more than 64 junctions.
+	Memoize bool
+	// Parallel explores the first few levels of branches in parallel, with one
+	// goroutine per CPU.
+	Parallel bool
+}
+
+// LongestPath returns the length of the longest simple path from the
+// junction at from to the junction at to, which is a path that never visits
+// a junction twice. It returns false if there is no path. The problem is
+// NP-hard, so it is only feasible for graphs with a few dozen junctions.
+func (g *Junctions) LongestPath(from, to image.Point, opts LongestPathOptions) (int, bool) {
+	return g.LongestPathContext(context.Background(), from, to, opts)
+}
+
+// LongestPathContext is like LongestPath, except it gives up once ctx is
+// done, in which case it returns false.
+func (g *Junctions) LongestPathContext(ctx context.Context, from, to image.Point, opts LongestPathOptions) (int, bool) {
+	fromIx, ok1 := g.Index(from)
+	toIx, ok2 := g.Index(to)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+
+	s := newLongestSearch(ctx, g, toIx, opts)
+	var dist int
+	if opts.Parallel {
+		dist = s.parallel(fromIx)
+	} else {
+		dist = s.clone().longest(fromIx, newBitset(g.Len()))
+	}
+	if ctx.Err() != nil {
+		return 0, false
+	}
+	return dist, dist >= 0
+}
+
+type longestSearch struct {
+	ctx  context.Context
+	g    *Junctions
+	to   int
+	last int // the only junction with an edge to the target, or -1
+	memo map[longestMemoKey]int
+	iter int
+}
+
+type longestMemoKey struct {
+	at      int
+	visited uint64
+}
+
+func newLongestSearch(ctx context.Context, g *Junctions, to int, opts LongestPathOptions) *longestSearch {
+	s := &longestSearch{ctx: ctx, g: g, to: to, last: -1}
+	if opts.Memoize && g.Len() <= 64 {
+		s.memo = make(map[longestMemoKey]int)
+	}
+
+	// If only one junction leads to the target, then any path that reaches
+	// that junction must go to the target right away, since it can never
+	// come back to it.
+	preds := aocutil.NewSet[int](0)
+	for from, edges := range g.Edges {
+		for _, e := range edges {
+			if e.To == to && from != to {
+				preds.Add(from)
+			}
+		}
+	}
+	if len(preds) == 1 {
+		for from := range preds {
+			s.last = from
+		}
+	}
+
+	return s
+}
+
+// clone returns a copy of the search with its own memo, so that it can be
+// used by another goroutine.
+func (s *longestSearch) clone() *longestSearch {
+	c := *s
+	if s.memo != nil {
+		c.memo = make(map[longestMemoKey]int)
+	}
+	return &c
+}
+
+// longest returns the longest distance from at to the target without
+// visiting any junction in visited, or -1 if the target cannot be reached or
+// the search is canceled. visited is restored before returning.
+func (s *longestSearch) longest(at int, visited bitset) int {
+	if at == s.to {
+		return 0
+	}
+
+	if aocutil.Canceled(s.ctx, &s.iter) {
+		return -1
+	}
+
+	key := longestMemoKey{at: at}
+	if s.memo != nil {
+		key.visited = visited[0]
+		if dist, ok := s.memo[key]; ok {
+			return dist
+		}
+	}
+
+	visited.set(at)
+	best := -1
+	for _, e := range s.edges(at) {
+		if visited.has(e.To) {
+			continue
+		}
+		if dist := s.longest(e.To, visited); dist >= 0 {
+			best = max(best, dist+e.Weight)
+		}
+	}
+	visited.clear(at)
+
+	if s.memo != nil {
+		s.memo[key] = best
+	}
+	return best
+}
+
+func (s *longestSearch) edges(at int) []Edge {
+	if at != s.last {
+		return s.g.Edges[at]
+	}
+	var longest []Edge
+	for _, e := range s.g.Edges[at] {
+		if e.To == s.to && (longest == nil || e.Weight > longest[0].Weight) {
+			longest = []Edge{e}
+		}
+	}
+	return longest
+}
+
+// longestPrefix is a partial path that is explored by one goroutine.
+type longestPrefix struct {
+	at      int
+	visited bitset
+	dist    int
+}
+
+// parallel expands the paths from the start breadth-first until there are
+// enough of them to keep every CPU busy, then finishes each of them in its
+// own goroutine.
+func (s *longestSearch) parallel(from int) int {
+	workers := runtime.GOMAXPROCS(0)
+
+	prefixes := []longestPrefix{{at: from, visited: newBitset(s.g.Len())}}
+	for len(prefixes) < 4*workers {
+		var next []longestPrefix
+		var expanded bool
+		for _, p := range prefixes {
+			if p.at == s.to {
+				next = append(next, p)
+				continue
+			}
+			expanded = true
+			for _, e := range s.edges(p.at) {
+				if p.visited.has(e.To) || e.To == p.at {
+					continue
+				}
+				visited := p.visited.clone()
+				visited.set(p.at)
+				next = append(next, longestPrefix{e.To, visited, p.dist + e.Weight})
+			}
+		}
+		prefixes = next
+		if !expanded {
+			// Every path has reached the target.
+			break
+		}
+	}
+
+	results := make([]int, len(prefixes))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	var panicked any
+	var panicOnce sync.Once
+
+	for i := 0; i < min(workers, len(prefixes)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				// Panics are re-panicked in the calling goroutine where
+				// they can be recovered, e.g. by aoctest.
+				if r := recover(); r != nil {
+					panicOnce.Do(func() { panicked = r })
+					for range jobs {
+					}
+				}
+			}()
+
+			search := s.clone()
+			for i := range jobs {
+				p := prefixes[i]
+				results[i] = -1
+				if dist := search.longest(p.at, p.visited); dist >= 0 {
+					results[i] = p.dist + dist
+				}
+			}
+		}()
+	}
+
+	for i := range prefixes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if panicked != nil {
+		panic(panicked)
+	}
+
+	best := -1
+	for _, dist := range results {
+		best = max(best, dist)
+	}
+	return best
+}
+
+// bitset is a set of junction indices.
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) has(i int) bool { return b[i/64]&(1<<(i%64)) != 0 }
+func (b bitset) set(i int)      { b[i/64] |= 1 << (i % 64) }
+func (b bitset) clear(i int)    { b[i/64] &^= 1 << (i % 64) }
+
+func (b bitset) clone() bitset {
+	return append(bitset(nil), b...)
+}
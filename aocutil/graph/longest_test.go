@@ -0,0 +1,144 @@
+package graph
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"libdb.so/aoc-2023/aocutil"
+)
+
+var testMaze = aocutil.NewMap2D(`
+#.#####################
+#.......#########...###
+#######.#########.#.###
+###.....#.>.>.###.#.###
+###v#####.#v#.###.#.###
+###.>...#.#.#.....#...#
+###v###.#.#.#########.#
+###...#.#.#.......#...#
+#####.#.#.#######.#.###
+#.....#.#.#.......#...#
+#.#####.#.#.#########v#
+#.#...#...#...###...>.#
+#.#.#v#######v###.###v#
+#...#.>.#...>.>.#.###.#
+#####v#.#.###v#.#.###.#
+#.....#...#...#.#.#...#
+#.#########.###.#.#.###
+#...###...#...#...#.###
+###.###.#.###v#####v###
+#...#...#.#.>.>.#.>.###
+#.###.###.#.###.#.#v###
+#.....###...###...#...#
+#####################.#
+`)
+
+var (
+	testMazeStart = image.Pt(1, 0)
+	testMazeEnd   = image.Pt(21, 22)
+)
+
+var testSlopes = map[byte]image.Point{
+	'^': aocutil.VecUp,
+	'v': aocutil.VecDown,
+	'<': aocutil.VecLeft,
+	'>': aocutil.VecRight,
+}
+
+// slopeNeighbors only allows walking down slopes.
+func slopeNeighbors(pt image.Point) aocutil.Iter[image.Point] {
+	dirs := aocutil.CardinalDirections
+	if d, ok := testSlopes[testMaze.At(pt)]; ok {
+		dirs = []image.Point{d}
+	}
+	return func(yield func(image.Point) bool) {
+		for next, b := range testMaze.Neighbors(pt, dirs) {
+			if b != '#' && !yield(next) {
+				return
+			}
+		}
+	}
+}
+
+var allLongestPathOptions = []LongestPathOptions{
+	{},
+	{Memoize: true},
+	{Parallel: true},
+	{Memoize: true, Parallel: true},
+}
+
+func TestCompress(t *testing.T) {
+	g := CompressMap(testMaze, testMazeStart, func(b byte) bool { return b != '#' })
+	// The start, the exit and the 7 junctions in between.
+	assert.Equal(t, 9, g.Len())
+
+	start, ok := g.Index(testMazeStart)
+	assert.True(t, ok)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, []Edge{{To: 1, Weight: 15}}, g.Edges[start])
+
+	// Undirected mazes have every edge in both directions.
+	for from, edges := range g.Edges {
+		for _, e := range edges {
+			assert.True(t, hasEdge(g, e.To, from, e.Weight), "edge %d -> %d", e.To, from)
+		}
+	}
+}
+
+func hasEdge(g *Junctions, from, to, weight int) bool {
+	for _, e := range g.Edges[from] {
+		if e.To == to && e.Weight == weight {
+			return true
+		}
+	}
+	return false
+}
+
+func TestJunctions_LongestPath(t *testing.T) {
+	undirected := CompressMap(testMaze, testMazeStart, func(b byte) bool { return b != '#' })
+	directed := Compress(testMazeStart, slopeNeighbors)
+
+	for _, opts := range allLongestPathOptions {
+		dist, ok := directed.LongestPath(testMazeStart, testMazeEnd, opts)
+		assert.True(t, ok)
+		assert.Equal(t, 94, dist, "directed with %+v", opts)
+
+		dist, ok = undirected.LongestPath(testMazeStart, testMazeEnd, opts)
+		assert.True(t, ok)
+		assert.Equal(t, 154, dist, "undirected with %+v", opts)
+	}
+}
+
+func TestJunctions_LongestPath_unreachable(t *testing.T) {
+	directed := Compress(testMazeStart, slopeNeighbors)
+	for _, opts := range allLongestPathOptions {
+		// Slopes cannot be walked up.
+		_, ok := directed.LongestPath(testMazeEnd, testMazeStart, opts)
+		assert.False(t, ok, "with %+v", opts)
+	}
+
+	_, ok := directed.LongestPath(testMazeStart, image.Pt(0, 0), LongestPathOptions{})
+	assert.False(t, ok)
+}
+
+func TestJunctions_LongestPathContext_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	undirected := CompressMap(testMaze, testMazeStart, func(b byte) bool { return b != '#' })
+	for _, opts := range allLongestPathOptions {
+		_, ok := undirected.LongestPathContext(ctx, testMazeStart, testMazeEnd, opts)
+		assert.False(t, ok, "with %+v", opts)
+	}
+}
+
+func TestJunctions_Graph(t *testing.T) {
+	g := Compress(testMazeStart, slopeNeighbors)
+	v := g.Graph(nil)
+	assert.Equal(t, g.Len(), len(v.Nodes))
+	assert.Equal(t, testMazeStart.String(), v.Nodes[0].ID)
+	assert.Equal(t, g.Edges[0][0].Weight, v.Edges[0].Weight)
+	assert.True(t, v.Directed)
+}
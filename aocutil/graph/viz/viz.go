@@ -0,0 +1,356 @@
+// Package viz describes graphs for visualization and renders them to
+// Graphviz DOT, Mermaid and JSON. Graphs can also be rendered to images if the
+// Graphviz dot binary is installed.
+package viz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultHighlightColor is the color of highlighted nodes and edges if
+// Graph.HighlightColor is empty.
+const DefaultHighlightColor = "red"
+
+// Graph is a graph of nodes and edges. Nodes and edges are rendered in the
+// order they were added.
+type Graph struct {
+	Name     string  `json:"name,omitempty"`
+	Directed bool    `json:"directed"`
+	Nodes    []*Node `json:"nodes"`
+	Edges    []*Edge `json:"edges"`
+	// HighlightColor is the color of highlighted nodes and edges. It defaults
+	// to DefaultHighlightColor.
+	HighlightColor string `json:"highlightColor,omitempty"`
+
+	index map[string]*Node
+}
+
+// Node is a node in a graph. Colors are either names known to both Graphviz
+// and CSS, such as "red", or hexadecimal like "#ff0000".
+type Node struct {
+	ID string `json:"id"`
+	// Label is the text shown on the node. It defaults to the ID.
+	Label string `json:"label,omitempty"`
+	// Color is the fill color of the node.
+	Color string `json:"color,omitempty"`
+	// Shape is the shape of the node: "box", "circle", "diamond" or anything
+	// else Graphviz understands. It defaults to an ellipse.
+	Shape       string `json:"shape,omitempty"`
+	Highlighted bool   `json:"highlighted,omitempty"`
+}
+
+// Edge is an edge between two nodes.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Label is the text shown on the edge. If it is empty and Weight is not 0,
+	// then the weight is shown instead.
+	Label  string `json:"label,omitempty"`
+	Weight int    `json:"weight,omitempty"`
+	// Color is the color of the edge.
+	Color       string `json:"color,omitempty"`
+	Highlighted bool   `json:"highlighted,omitempty"`
+}
+
+// New creates a new empty graph.
+func New(directed bool) *Graph {
+	return &Graph{Directed: directed}
+}
+
+// Node returns the node with the given ID, or nil if there is none.
+func (g *Graph) Node(id string) *Node {
+	return g.index[id]
+}
+
+// AddNode adds a node with the given ID and returns it. If the node already
+// exists, then the existing node is returned.
+func (g *Graph) AddNode(id string) *Node {
+	if n, ok := g.index[id]; ok {
+		return n
+	}
+	if g.index == nil {
+		g.index = make(map[string]*Node)
+	}
+	n := &Node{ID: id}
+	g.Nodes = append(g.Nodes, n)
+	g.index[id] = n
+	return n
+}
+
+// AddEdge adds an edge between the nodes with the given IDs and returns it.
+// Nodes that do not exist yet are added.
+func (g *Graph) AddEdge(from, to string) *Edge {
+	g.AddNode(from)
+	g.AddNode(to)
+	e := &Edge{From: from, To: to}
+	g.Edges = append(g.Edges, e)
+	return e
+}
+
+// findEdge returns the first edge from a to b. If the graph is undirected,
+// then an edge from b to a is also returned.
+func (g *Graph) findEdge(a, b string) *Edge {
+	for _, e := range g.Edges {
+		if e.From == a && e.To == b || !g.Directed && e.From == b && e.To == a {
+			return e
+		}
+	}
+	return nil
+}
+
+// HighlightPath highlights the nodes with the given IDs and the edges between
+// consecutive ones. Nodes and edges that do not exist are ignored.
+func (g *Graph) HighlightPath(ids ...string) {
+	for i, id := range ids {
+		if n := g.Node(id); n != nil {
+			n.Highlighted = true
+		}
+		if i > 0 {
+			if e := g.findEdge(ids[i-1], id); e != nil {
+				e.Highlighted = true
+			}
+		}
+	}
+}
+
+func (g *Graph) highlightColor() string {
+	if g.HighlightColor != "" {
+		return g.HighlightColor
+	}
+	return DefaultHighlightColor
+}
+
+func (n *Node) label() string {
+	if n.Label != "" {
+		return n.Label
+	}
+	return n.ID
+}
+
+func (e *Edge) label() string {
+	if e.Label == "" && e.Weight != 0 {
+		return strconv.Itoa(e.Weight)
+	}
+	return e.Label
+}
+
+// DOT returns the graph in the Graphviz DOT language.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+
+	kind, arrow := "graph", "--"
+	if g.Directed {
+		kind, arrow = "digraph", "->"
+	}
+
+	b.WriteString(kind)
+	if g.Name != "" {
+		b.WriteString(" " + dotQuote(g.Name))
+	}
+	b.WriteString(" {\n")
+
+	for _, n := range g.Nodes {
+		var attrs dotAttrs
+		if n.Label != "" {
+			attrs.add("label", n.Label)
+		}
+		if n.Shape != "" {
+			attrs.add("shape", n.Shape)
+		}
+		if n.Color != "" {
+			attrs.add("style", "filled")
+			attrs.add("fillcolor", n.Color)
+		}
+		if n.Highlighted {
+			attrs.add("color", g.highlightColor())
+			attrs.add("penwidth", "3")
+		}
+		fmt.Fprintf(&b, "  %s%s;\n", dotQuote(n.ID), attrs)
+	}
+
+	for _, e := range g.Edges {
+		var attrs dotAttrs
+		if label := e.label(); label != "" {
+			attrs.add("label", label)
+		}
+		switch {
+		case e.Highlighted:
+			attrs.add("color", g.highlightColor())
+			attrs.add("penwidth", "3")
+		case e.Color != "":
+			attrs.add("color", e.Color)
+		}
+		fmt.Fprintf(&b, "  %s %s %s%s;\n", dotQuote(e.From), arrow, dotQuote(e.To), attrs)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+type dotAttrs []string
+
+func (a *dotAttrs) add(k, v string) {
+	*a = append(*a, k+"="+dotQuote(v))
+}
+
+func (a dotAttrs) String() string {
+	if len(a) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(a, ", ") + "]"
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. Mermaid only allows
+// simple node IDs, so nodes are renamed to n0, n1 and so on, and their IDs
+// are used as labels if they have none.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		id := "n" + strconv.Itoa(i)
+		ids[n.ID] = id
+
+		open, close := mermaidShape(n.Shape)
+		fmt.Fprintf(&b, "  %s%s%s%s\n", id, open, mermaidQuote(n.label()), close)
+	}
+
+	arrow := "---"
+	if g.Directed {
+		arrow = "-->"
+	}
+	for _, e := range g.Edges {
+		if label := e.label(); label != "" {
+			fmt.Fprintf(&b, "  %s %s|%s| %s\n", ids[e.From], arrow, mermaidQuote(label), ids[e.To])
+		} else {
+			fmt.Fprintf(&b, "  %s %s %s\n", ids[e.From], arrow, ids[e.To])
+		}
+	}
+
+	for _, n := range g.Nodes {
+		var styles []string
+		if n.Color != "" {
+			styles = append(styles, "fill:"+n.Color)
+		}
+		if n.Highlighted {
+			styles = append(styles, "stroke:"+g.highlightColor(), "stroke-width:3px")
+		}
+		if len(styles) > 0 {
+			fmt.Fprintf(&b, "  style %s %s\n", ids[n.ID], strings.Join(styles, ","))
+		}
+	}
+
+	for i, e := range g.Edges {
+		var color string
+		switch {
+		case e.Highlighted:
+			color = g.highlightColor() + ",stroke-width:3px"
+		case e.Color != "":
+			color = e.Color
+		default:
+			continue
+		}
+		fmt.Fprintf(&b, "  linkStyle %d stroke:%s\n", i, color)
+	}
+
+	return b.String()
+}
+
+func mermaidShape(shape string) (open, close string) {
+	switch shape {
+	case "box", "rect", "square":
+		return "[", "]"
+	case "circle":
+		return "((", "))"
+	case "diamond":
+		return "{", "}"
+	default:
+		return "(", ")"
+	}
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// JSON returns the graph as indented JSON.
+func (g *Graph) JSON() []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(g); err != nil {
+		panic(err) // a Graph always marshals
+	}
+	return b.Bytes()
+}
+
+// Render runs the Graphviz dot binary to render the graph in the given
+// format, such as "png" or "svg". It fails if dot is not installed.
+func (g *Graph) Render(format string) ([]byte, error) {
+	dot, err := exec.LookPath("dot")
+	if err != nil {
+		return nil, fmt.Errorf("cannot render graph: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(dot, "-T"+format)
+	cmd.Stdin = strings.NewReader(g.DOT())
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("dot: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Image renders the graph into an image using the Graphviz dot binary. The
+// image can be shown with aocutil.OpenImage.
+func (g *Graph) Image() (image.Image, error) {
+	b, err := g.Render("png")
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(b))
+}
+
+// Save saves the graph to dst. The format is chosen by its extension: .dot or
+// .gv for DOT, .mmd for Mermaid and .json for JSON. Any other extension, such
+// as .png or .svg, is rendered with the Graphviz dot binary.
+func (g *Graph) Save(dst string) error {
+	var data []byte
+	switch ext := filepath.Ext(dst); ext {
+	case ".dot", ".gv":
+		data = []byte(g.DOT())
+	case ".mmd":
+		data = []byte(g.Mermaid())
+	case ".json":
+		data = g.JSON()
+	case "":
+		return fmt.Errorf("missing extension in %q", dst)
+	default:
+		var err error
+		data, err = g.Render(strings.TrimPrefix(ext, "."))
+		if err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(dst, data, 0644)
+}
@@ -0,0 +1,125 @@
+package viz
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func testGraph() *Graph {
+	g := New(true)
+	g.Name = "test"
+	g.AddNode("a").Shape = "box"
+	g.AddNode("b").Color = "#00ff00"
+	g.AddNode("c").Label = `say "hi"`
+	g.AddEdge("a", "b").Weight = 3
+	g.AddEdge("b", "c").Label = "next"
+	g.AddEdge("a", "c").Color = "blue"
+	g.HighlightPath("a", "b", "c")
+	return g
+}
+
+func TestGraph_AddNode(t *testing.T) {
+	g := New(false)
+	a := g.AddNode("a")
+	assert.True(t, a == g.AddNode("a"))
+	assert.True(t, a == g.Node("a"))
+	assert.Zero(t, g.Node("b"))
+
+	g.AddEdge("a", "b")
+	assert.Equal(t, 2, len(g.Nodes))
+	assert.NotZero(t, g.Node("b"))
+}
+
+func TestGraph_HighlightPath(t *testing.T) {
+	g := New(false)
+	g.AddEdge("a", "b")
+	g.AddEdge("c", "b")
+	g.AddEdge("c", "d")
+	g.HighlightPath("a", "b", "c", "x")
+
+	var highlighted []bool
+	for _, e := range g.Edges {
+		highlighted = append(highlighted, e.Highlighted)
+	}
+	assert.Equal(t, []bool{true, true, false}, highlighted)
+	assert.True(t, g.Node("c").Highlighted)
+	assert.False(t, g.Node("d").Highlighted)
+}
+
+func TestGraph_DOT(t *testing.T) {
+	assert.Equal(t, `digraph "test" {
+  "a" [shape="box", color="red", penwidth="3"];
+  "b" [style="filled", fillcolor="#00ff00", color="red", penwidth="3"];
+  "c" [label="say \"hi\"", color="red", penwidth="3"];
+  "a" -> "b" [label="3", color="red", penwidth="3"];
+  "b" -> "c" [label="next", color="red", penwidth="3"];
+  "a" -> "c" [color="blue"];
+}
+`, testGraph().DOT())
+
+	g := New(false)
+	g.AddEdge("x", "y")
+	assert.Equal(t, "graph {\n  \"x\";\n  \"y\";\n  \"x\" -- \"y\";\n}\n", g.DOT())
+}
+
+func TestGraph_Mermaid(t *testing.T) {
+	assert.Equal(t, `flowchart LR
+  n0["a"]
+  n1("b")
+  n2("say #quot;hi#quot;")
+  n0 -->|"3"| n1
+  n1 -->|"next"| n2
+  n0 --> n2
+  style n0 stroke:red,stroke-width:3px
+  style n1 fill:#00ff00,stroke:red,stroke-width:3px
+  style n2 stroke:red,stroke-width:3px
+  linkStyle 0 stroke:red,stroke-width:3px
+  linkStyle 1 stroke:red,stroke-width:3px
+  linkStyle 2 stroke:blue
+`, testGraph().Mermaid())
+}
+
+func TestGraph_JSON(t *testing.T) {
+	g := testGraph()
+
+	var decoded Graph
+	assert.NoError(t, json.Unmarshal(g.JSON(), &decoded))
+	assert.Equal(t, g.Nodes, decoded.Nodes)
+	assert.Equal(t, g.Edges, decoded.Edges)
+	assert.Equal(t, g.Name, decoded.Name)
+	assert.True(t, decoded.Directed)
+}
+
+func TestGraph_Save(t *testing.T) {
+	dir := t.TempDir()
+	g := testGraph()
+
+	for name, want := range map[string]string{
+		"g.dot":  g.DOT(),
+		"g.mmd":  g.Mermaid(),
+		"g.json": string(g.JSON()),
+	} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, g.Save(path))
+		b, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(b))
+	}
+
+	assert.Error(t, g.Save(filepath.Join(dir, "g")))
+}
+
+func TestGraph_Image(t *testing.T) {
+	if _, err := exec.LookPath("dot"); err != nil {
+		t.Skip("dot is not installed")
+	}
+
+	img, err := testGraph().Image()
+	assert.NoError(t, err)
+	assert.True(t, img.Bounds().Dx() > 0)
+}
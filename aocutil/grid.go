@@ -0,0 +1,184 @@
+package aocutil
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// Grid is a 2D grid of values of any comparable type. It is the generic
+// counterpart of Map2D. Like image.Paletted, the values are stored
+// contiguously in row-major order, and the value at (x, y) is at
+// Data[(y-Bounds.Min.Y)*Stride+(x-Bounds.Min.X)].
+type Grid[T comparable] struct {
+	Data   []T
+	Stride int
+	Bounds image.Rectangle
+}
+
+// NewGrid creates a new grid with the given bounds filled with zero values.
+func NewGrid[T comparable](bounds image.Rectangle) Grid[T] {
+	bounds = bounds.Canon()
+	return Grid[T]{
+		Data:   make([]T, bounds.Dx()*bounds.Dy()),
+		Stride: bounds.Dx(),
+		Bounds: bounds,
+	}
+}
+
+// NewGridFromData creates a new grid from the given rows. All rows must have
+// the same length.
+func NewGridFromData[T comparable](data [][]T) Grid[T] {
+	var width int
+	if len(data) > 0 {
+		width = len(data[0])
+	}
+
+	g := NewGrid[T](image.Rect(0, 0, width, len(data)))
+	for y, row := range data {
+		Assertf(len(row) == width, "NewGridFromData: row %d has length %d, expected %d", y, len(row), width)
+		copy(g.Row(y), row)
+	}
+	return g
+}
+
+// NewGridFromMap2D creates a new grid with the same bounds as m, with each
+// byte converted using f.
+func NewGridFromMap2D[T comparable](m Map2D, f func(byte) T) Grid[T] {
+	g := NewGrid[T](m.Bounds)
+	for pt, v := range m.All() {
+		g.Set(pt, f(v))
+	}
+	return g
+}
+
+// MapGrid returns a new grid with each value of g converted using f.
+func MapGrid[T, U comparable](g Grid[T], f func(T) U) Grid[U] {
+	n := NewGrid[U](g.Bounds)
+	for pt, v := range g.All() {
+		n.Set(pt, f(v))
+	}
+	return n
+}
+
+func (g Grid[T]) index(p image.Point) int {
+	return (p.Y-g.Bounds.Min.Y)*g.Stride + (p.X - g.Bounds.Min.X)
+}
+
+// Rect returns the bounds of the grid. It is the same as g.Bounds.
+func (g Grid[T]) Rect() image.Rectangle { return g.Bounds }
+
+// At returns the value at the given point. If the point is out of bounds,
+// then the zero value is returned.
+func (g Grid[T]) At(p image.Point) T {
+	if !p.In(g.Bounds) {
+		var z T
+		return z
+	}
+	return g.Data[g.index(p)]
+}
+
+// Set sets the value at the given point. Points out of bounds are ignored.
+func (g Grid[T]) Set(p image.Point, v T) {
+	if !p.In(g.Bounds) {
+		return
+	}
+	g.Data[g.index(p)] = v
+}
+
+// Row returns the values of row y. The returned slice shares the grid's
+// storage. It returns nil if y is out of bounds.
+func (g Grid[T]) Row(y int) []T {
+	if y < g.Bounds.Min.Y || y >= g.Bounds.Max.Y {
+		return nil
+	}
+	i := g.index(image.Pt(g.Bounds.Min.X, y))
+	return g.Data[i : i+g.Bounds.Dx() : i+g.Bounds.Dx()]
+}
+
+// Clone makes a copy of the grid.
+func (g Grid[T]) Clone() Grid[T] {
+	n := NewGrid[T](g.Bounds)
+	for y := g.Bounds.Min.Y; y < g.Bounds.Max.Y; y++ {
+		copy(n.Row(y), g.Row(y))
+	}
+	return n
+}
+
+// Equal returns true if the grids are equal.
+func (g Grid[T]) Equal(other Grid[T]) bool {
+	if g.Bounds != other.Bounds {
+		return false
+	}
+	for y := g.Bounds.Min.Y; y < g.Bounds.Max.Y; y++ {
+		a, b := g.Row(y), other.Row(y)
+		for x := range a {
+			if a[x] != b[x] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Transpose returns a transposed copy of the grid.
+func (g Grid[T]) Transpose() Grid[T] {
+	b := g.Bounds
+	n := NewGrid[T](image.Rect(b.Min.Y, b.Min.X, b.Max.Y, b.Max.X))
+	for pt, v := range g.All() {
+		n.Set(image.Pt(pt.Y, pt.X), v)
+	}
+	return n
+}
+
+// All returns an iterator that iterates over all points in the grid.
+func (g Grid[T]) All() Iter2[image.Point, T] {
+	return g.AllWithin(g.Bounds)
+}
+
+// AllWithin returns an iterator that iterates over all points within the
+// given rectangle.
+func (g Grid[T]) AllWithin(r image.Rectangle) Iter2[image.Point, T] {
+	r = r.Canon()
+	r = r.Intersect(g.Bounds)
+
+	return func(yield func(image.Point, T) bool) {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				pt := image.Pt(x, y)
+				if !yield(pt, g.Data[g.index(pt)]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// String returns a string representation of the grid, with each row on its
+// own line. Values are formatted with fmt.Sprint; if any of them is wider
+// than a single character, then they are padded and separated by spaces.
+func (g Grid[T]) String() string {
+	cells := make([]string, 0, g.Bounds.Dx()*g.Bounds.Dy())
+	var width int
+	for _, v := range g.All() {
+		s := fmt.Sprint(v)
+		width = max(width, len(s))
+		cells = append(cells, s)
+	}
+
+	var sb strings.Builder
+	for i, cell := range cells {
+		x := i % g.Bounds.Dx()
+		if width > 1 {
+			if x > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(strings.Repeat(" ", width-len(cell)))
+		}
+		sb.WriteString(cell)
+		if x == g.Bounds.Dx()-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package aocutil
+
+import (
+	"image"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestGrid(t *testing.T) {
+	g := NewGrid[int](image.Rect(-1, -1, 2, 1))
+	assert.Equal(t, 6, len(g.Data))
+
+	g.Set(image.Pt(-1, -1), 1)
+	g.Set(image.Pt(1, 0), 10)
+	g.Set(image.Pt(5, 5), 99) // ignored
+	assert.Equal(t, 1, g.At(image.Pt(-1, -1)))
+	assert.Equal(t, 10, g.At(image.Pt(1, 0)))
+	assert.Equal(t, 0, g.At(image.Pt(5, 5)))
+	assert.Equal(t, []int{0, 0, 10}, g.Row(0))
+	assert.Equal(t, nil, g.Row(1))
+	assert.Equal(t, " 1  0  0\n 0  0 10\n", g.String())
+
+	c := g.Clone()
+	assert.True(t, c.Equal(g))
+	c.Set(image.Pt(0, 0), 5)
+	assert.False(t, c.Equal(g))
+
+	var pts []image.Point
+	for pt, v := range g.AllWithin(image.Rect(0, -5, 5, 5)) {
+		if v == 0 {
+			pts = append(pts, pt)
+		}
+	}
+	assert.Equal(t, []image.Point{{0, -1}, {1, -1}, {0, 0}}, pts)
+}
+
+func TestGrid_Transpose(t *testing.T) {
+	g := NewGridFromData([][]int{
+		{1, 2, 3},
+		{4, 5, 6},
+	})
+
+	assert.Equal(t, NewGridFromData([][]int{
+		{1, 4},
+		{2, 5},
+		{3, 6},
+	}), g.Transpose())
+}
+
+func TestNewGridFromMap2D(t *testing.T) {
+	m := NewMap2D("12\n34\n")
+	g := NewGridFromMap2D(m, func(b byte) int { return int(b - '0') })
+	assert.Equal(t, "12\n34\n", g.String())
+	assert.Equal(t, 4, g.At(image.Pt(1, 1)))
+
+	scaled := MapGrid(g, func(v int) int { return v * 5 })
+	assert.Equal(t, " 5 10\n15 20\n", scaled.String())
+}
@@ -3,35 +3,68 @@ package aocutil
 import (
 	"fmt"
 	"image"
+	"image/gif"
 	"image/png"
-	"log"
+	"io"
 	"math"
 	"os"
-	"os/exec"
 	"path/filepath"
 
 	"golang.org/x/exp/constraints"
 )
 
-// SaveImage saves the given image to a PNG image.
+// SaveImage saves the given image to a PNG or GIF image, depending on the
+// extension of dst. Use Recorder to save animations.
 func SaveImage(img image.Image, dst string) {
-	if ext := filepath.Ext(dst); ext != ".png" {
-		panic(fmt.Errorf("invalid extension %q, only .png is supported", ext))
+	var encode func(io.Writer, image.Image) error
+	switch ext := filepath.Ext(dst); ext {
+	case ".png":
+		encode = png.Encode
+	case ".gif":
+		encode = func(w io.Writer, img image.Image) error { return gif.Encode(w, img, nil) }
+	default:
+		panic(fmt.Errorf("invalid extension %q, only .png and .gif are supported", ext))
 	}
 	f := E2(os.Create(dst))
 	defer f.Close()
-	E1(png.Encode(f, img))
+	E1(encode(f, img))
 }
 
-// OpenImage saves the given image to a temporary PNG image and opens it.
-func OpenImage(img image.Image) {
-	f := E2(os.CreateTemp("", "aocutil-*.png"))
-	defer f.Close()
-	E1(png.Encode(f, img))
-	log.Printf("opening PNG at %q", f.Name())
-	cmd := exec.Command("xdg-open", f.Name())
-	cmd.Stderr = os.Stderr
-	E1(cmd.Start())
+// ScaleImage upscales img by an integer factor using nearest-neighbor
+// sampling, so that each pixel becomes a factor×factor square. The bounds are
+// scaled as well. Paletted images stay paletted.
+func ScaleImage(img image.Image, factor int) image.Image {
+	Assertf(factor > 0, "ScaleImage: invalid factor %d", factor)
+
+	b := img.Bounds()
+	scaled := image.Rectangle{Min: b.Min.Mul(factor), Max: b.Max.Mul(factor)}
+
+	if src, ok := img.(*image.Paletted); ok {
+		dst := image.NewPaletted(scaled, src.Palette)
+		for y := scaled.Min.Y; y < scaled.Max.Y; y++ {
+			for x := scaled.Min.X; x < scaled.Max.X; x++ {
+				dst.SetColorIndex(x, y, src.ColorIndexAt(floorDiv(x, factor), floorDiv(y, factor)))
+			}
+		}
+		return dst
+	}
+
+	dst := image.NewNRGBA(scaled)
+	for y := scaled.Min.Y; y < scaled.Max.Y; y++ {
+		for x := scaled.Min.X; x < scaled.Max.X; x++ {
+			dst.Set(x, y, img.At(floorDiv(x, factor), floorDiv(y, factor)))
+		}
+	}
+	return dst
+}
+
+// floorDiv divides a by b, rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
 }
 
 // ScalarType is a type that can be used as a scalar.
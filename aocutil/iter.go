@@ -1,6 +1,10 @@
 package aocutil
 
-import "golang.org/x/exp/constraints"
+import (
+	"context"
+
+	"golang.org/x/exp/constraints"
+)
 
 // Iter copies x/exp/xiter.
 type Iter[T any] func(yield func(T) bool)
@@ -103,9 +107,15 @@ func SliceIter[T any](vs []T) Iter[T] {
 
 // Range returns an iterator that yields the items in the range.
 func Range[T constraints.Integer | constraints.Float](start, end T) Iter[T] {
+	return RangeContext(context.Background(), start, end)
+}
+
+// RangeContext is like Range, except it stops yielding once ctx is done.
+func RangeContext[T constraints.Integer | constraints.Float](ctx context.Context, start, end T) Iter[T] {
 	return func(yield func(T) bool) {
+		var n int
 		for i := start; i < end; i++ {
-			if !yield(i) {
+			if Canceled(ctx, &n) || !yield(i) {
 				break
 			}
 		}
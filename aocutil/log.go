@@ -0,0 +1,304 @@
+package aocutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	logLevel  slog.LevelVar
+	logJSON   = false
+	logColors = os.Getenv("NO_COLOR") == ""
+)
+
+// levelSilent is above every level that is logged in practice.
+const levelSilent = slog.Level(1 << 10)
+
+// globalScope is the scope set by ScopeLogf. It applies to every log record.
+var globalScope atomic.Pointer[string]
+
+// setupLogging makes a new log handler writing to stderr the default for both
+// the slog and log packages.
+func setupLogging() {
+	log.SetFlags(0)
+	slog.SetDefault(slog.New(NewLogHandler(os.Stderr, LogOptions{
+		Level:    &logLevel,
+		JSON:     logJSON,
+		NoColors: !logColors,
+	})))
+}
+
+type logScopeKey struct{}
+
+// WithLogScope returns a copy of ctx that has a new log scope nested inside
+// its current scope. Records logged with the returned context are prefixed
+// with the scope. If f is empty, then the scope is only indented.
+func WithLogScope(ctx context.Context, f string, v ...any) context.Context {
+	scope, _ := ctx.Value(logScopeKey{}).(string)
+	return context.WithValue(ctx, logScopeKey{}, appendScope(scope, f, v...))
+}
+
+// LogScope returns the log scope of ctx, including the scope set by
+// ScopeLogf.
+func LogScope(ctx context.Context) string {
+	var scope string
+	if global := globalScope.Load(); global != nil {
+		scope = *global
+	}
+	if ctx != nil {
+		s, _ := ctx.Value(logScopeKey{}).(string)
+		scope += s
+	}
+	return scope
+}
+
+func appendScope(scope, f string, v ...any) string {
+	if f == "" {
+		return scope + "."
+	}
+	return scope + fmt.Sprintf(f, v...) + ": "
+}
+
+// ScopeLogf enters a log scope for every record logged until unscope is
+// called.
+//
+// Deprecated: The scope is shared by the whole process, so it is mixed up
+// between parts running concurrently. Use WithLogScope instead.
+func ScopeLogf(f string, v ...any) (unscope func()) {
+	old := LogScope(nil)
+	new := appendScope(old, f, v...)
+	globalScope.Store(&new)
+	return func() { globalScope.Store(&old) }
+}
+
+type scopeTrackerKey struct{}
+
+// trackLogScope returns a copy of ctx that remembers the scope of the last
+// record logged with it or its children. Records filtered out by their level
+// are not remembered, so that checking the level stays cheap.
+func trackLogScope(ctx context.Context) (context.Context, *atomic.Pointer[string]) {
+	var last atomic.Pointer[string]
+	return context.WithValue(ctx, scopeTrackerKey{}, &last), &last
+}
+
+func recordLogScope(ctx context.Context, scope string) {
+	if ctx == nil {
+		return
+	}
+	if last, ok := ctx.Value(scopeTrackerKey{}).(*atomic.Pointer[string]); ok {
+		last.Store(&scope)
+	}
+}
+
+// IsSilent returns true if informational logs are not printed, such as when
+// the -s flag is given.
+func IsSilent() bool { return logLevel.Level() > slog.LevelInfo }
+
+// SilenceLogging disables logging below the warning level.
+func SilenceLogging() {
+	if logLevel.Level() < slog.LevelWarn {
+		logLevel.Set(slog.LevelWarn)
+	}
+}
+
+// withLoggingSilenced calls f with logging temporarily disabled.
+func withLoggingSilenced(f func()) {
+	old := logLevel.Level()
+	logLevel.Set(levelSilent)
+	defer logLevel.Set(old)
+	f()
+}
+
+// LogOptions are options for NewLogHandler.
+type LogOptions struct {
+	// Level is the minimum level that is logged. It defaults to
+	// slog.LevelInfo.
+	Level slog.Leveler
+	// JSON makes the handler write JSON lines instead of text.
+	JSON bool
+	// NoColors disables ANSI colors in text output.
+	NoColors bool
+}
+
+// NewLogHandler returns a slog.Handler that prefixes each record with the
+// time elapsed since the previous record and the record's log scope. Scopes
+// are carried through the context given to the slog.*Context functions; see
+// WithLogScope.
+func NewLogHandler(w io.Writer, opts LogOptions) slog.Handler {
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+	h := &logHandler{
+		state: &logState{
+			w:        w,
+			lastTime: time.Now(),
+		},
+		opts: opts,
+	}
+	if opts.JSON {
+		h.json = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: opts.Level})
+	}
+	return h
+}
+
+type logState struct {
+	mu       sync.Mutex
+	w        io.Writer
+	lastTime time.Time
+}
+
+// elapsed returns the time since the last call to elapsed.
+func (s *logState) elapsed(now time.Time) time.Duration {
+	d := now.Sub(s.lastTime)
+	s.lastTime = now
+	return d
+}
+
+type logHandler struct {
+	state *logState
+	opts  LogOptions
+
+	// json is the underlying handler for JSON output. The attributes and
+	// groups are replayed onto it for every record so that the scope stays at
+	// the top level.
+	json slog.Handler
+	ops  []func(slog.Handler) slog.Handler
+
+	// attrs are the preformatted attributes for text output. group is the
+	// prefix of the keys of the attributes added after them.
+	attrs string
+	group string
+}
+
+var _ slog.Handler = (*logHandler)(nil)
+
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	if h.json != nil {
+		h2.ops = append(slices.Clip(h.ops), func(h slog.Handler) slog.Handler {
+			return h.WithAttrs(attrs)
+		})
+	} else {
+		var b strings.Builder
+		for _, attr := range attrs {
+			appendAttr(&b, h.group, attr)
+		}
+		h2.attrs += b.String()
+	}
+	return &h2
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.ops = append(slices.Clip(h.ops), func(h slog.Handler) slog.Handler {
+		return h.WithGroup(name)
+	})
+	h2.group += name + "."
+	return &h2
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	scope := LogScope(ctx)
+	recordLogScope(ctx, scope)
+
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	d := h.state.elapsed(r.Time)
+
+	if h.json != nil {
+		var attrs []slog.Attr
+		attrs = append(attrs, slog.Duration("elapsed", d))
+		if scope != "" {
+			attrs = append(attrs, slog.String("scope", strings.TrimSuffix(scope, ": ")))
+		}
+		json := h.json.WithAttrs(attrs)
+		for _, op := range h.ops {
+			json = op(json)
+		}
+		return json.Handle(ctx, r)
+	}
+
+	gutter := fmt.Sprintf("+% 3d.%06ds ⎸ ", int(d.Seconds()), int(d.Microseconds())%1000000)
+	if !h.opts.NoColors {
+		gutter = "\033[38;5;248m" + gutter + "\033[0m"
+	}
+
+	prefix := gutter
+	if r.Level != slog.LevelInfo {
+		level := r.Level.String()
+		if !h.opts.NoColors {
+			level = "\033[" + levelColor(r.Level) + "m" + level + "\033[0m"
+		}
+		prefix += level + " "
+	}
+	prefix += scope
+
+	var b strings.Builder
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(attr slog.Attr) bool {
+		appendAttr(&b, h.group, attr)
+		return true
+	})
+	b.WriteByte('\n')
+
+	_, err := writePrefixed(h.state.w, []byte(b.String()), []byte(prefix))
+	return err
+}
+
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "31"
+	case level >= slog.LevelWarn:
+		return "33"
+	default:
+		return "38;5;244"
+	}
+}
+
+func appendAttr(b *strings.Builder, group string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			group += attr.Key + "."
+		}
+		for _, attr := range attr.Value.Group() {
+			appendAttr(b, group, attr)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(group)
+	b.WriteString(attr.Key)
+	b.WriteByte('=')
+	b.WriteString(value)
+}
@@ -0,0 +1,79 @@
+package aocutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+var logGutter = regexp.MustCompile(`(?m)^\+ +\d+\.\d{6}s ⎸ `)
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(&buf, LogOptions{
+		Level:    slog.LevelDebug,
+		NoColors: true,
+	}))
+
+	ctx := WithLogScope(context.Background(), "part %d", 1)
+	logger.InfoContext(ctx, "hello", "n", 1)
+
+	ctx = WithLogScope(ctx, "")
+	logger.With("a", "b c").WithGroup("g").DebugContext(ctx, "two\nlines", "x", true)
+
+	logger.Warn("unscoped")
+
+	assert.Equal(t, ""+
+		"part 1: hello n=1\n"+
+		"DEBUG part 1: .two\n"+
+		"DEBUG part 1: .lines a=\"b c\" g.x=true\n"+
+		"WARN unscoped\n",
+		logGutter.ReplaceAllString(buf.String(), ""))
+}
+
+func TestLogHandlerLevel(t *testing.T) {
+	var level slog.LevelVar
+	level.Set(slog.LevelWarn)
+
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(&buf, LogOptions{Level: &level}))
+
+	logger.Info("hidden")
+	assert.Equal(t, "", buf.String())
+
+	level.Set(slog.LevelInfo)
+	logger.Info("shown")
+	assert.NotEqual(t, "", buf.String())
+}
+
+func TestLogHandlerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(&buf, LogOptions{JSON: true}))
+
+	ctx := WithLogScope(context.Background(), "outer")
+	ctx = WithLogScope(ctx, "inner")
+	logger.WithGroup("g").InfoContext(ctx, "hello", "n", 1)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, any("outer: inner"), record["scope"])
+	assert.Equal(t, any("hello"), record["msg"])
+	assert.Equal(t, any(map[string]any{"n": 1.0}), record["g"])
+}
+
+func TestTrackLogScope(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(&buf, LogOptions{Level: slog.LevelInfo}))
+
+	ctx, last := trackLogScope(context.Background())
+	logger.InfoContext(WithLogScope(ctx, "loop"), "logged")
+	assert.Equal(t, "loop: ", *last.Load())
+
+	logger.DebugContext(WithLogScope(ctx, "filtered"), "filtered out")
+	assert.Equal(t, "loop: ", *last.Load())
+}
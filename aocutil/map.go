@@ -110,9 +110,17 @@ func (m Map2D) Draw(colorMap map[byte]color.RGBA) *image.Paletted {
 	palette := make(color.Palette, 1, 1+len(colorMap))
 	palette[0] = color.RGBA{0, 0, 0, 255}
 
+	// The palette is sorted by byte so that the same colors always give the
+	// same image.
+	keys := make([]byte, 0, len(colorMap))
+	for k := range colorMap {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
 	colorIx := make(map[byte]uint8)
-	for k, v := range colorMap {
-		palette = append(palette, v)
+	for _, k := range keys {
+		palette = append(palette, colorMap[k])
 		colorIx[k] = uint8(len(palette) - 1)
 	}
 
@@ -150,6 +158,95 @@ func (m Map2D) Transpose() Map2D {
 	return NewMap2DFromData(data)
 }
 
+// RotateCW returns a copy of the map rotated 90 degrees clockwise. The copy
+// has the same Bounds.Min as the map.
+func (m Map2D) RotateCW() Map2D {
+	n := m.rotated()
+	for y, line := range m.Data {
+		for x, v := range line {
+			n.Data[x][len(m.Data)-1-y] = v
+		}
+	}
+	return n
+}
+
+// RotateCCW returns a copy of the map rotated 90 degrees counter-clockwise.
+// The copy has the same Bounds.Min as the map.
+func (m Map2D) RotateCCW() Map2D {
+	n := m.rotated()
+	for y, line := range m.Data {
+		for x, v := range line {
+			n.Data[len(line)-1-x][y] = v
+		}
+	}
+	return n
+}
+
+// rotated returns an empty map with the width and height of m swapped.
+func (m Map2D) rotated() Map2D {
+	size := m.Bounds.Size()
+	return NewEmptyMap2D(image.Rectangle{
+		Min: m.Bounds.Min,
+		Max: m.Bounds.Min.Add(image.Pt(size.Y, size.X)),
+	})
+}
+
+// FlipH returns a copy of the map flipped horizontally, so that the left
+// and right sides are swapped.
+func (m Map2D) FlipH() Map2D {
+	n := m.Clone()
+	for _, line := range n.Data {
+		slices.Reverse(line)
+	}
+	return n
+}
+
+// FlipV returns a copy of the map flipped vertically, so that the top and
+// bottom sides are swapped.
+func (m Map2D) FlipV() Map2D {
+	n := m.Clone()
+	slices.Reverse(n.Data)
+	return n
+}
+
+// SubMap returns a view of the part of the map within r. The view shares the
+// map's storage, so setting a byte in one is visible in the other. Points keep
+// their coordinates, meaning that the view's Bounds is r clipped to the map's
+// Bounds.
+func (m Map2D) SubMap(r image.Rectangle) Map2D {
+	r = r.Canon().Intersect(m.Bounds)
+	rel := r.Sub(m.Bounds.Min)
+
+	data := make([][]byte, rel.Dy())
+	for i := range data {
+		data[i] = m.Data[rel.Min.Y+i][rel.Min.X:rel.Max.X:rel.Max.X]
+	}
+
+	return Map2D{Data: data, Bounds: r}
+}
+
+// Row returns row y of the map. The returned slice shares the map's storage.
+// It returns nil if y is out of bounds.
+func (m Map2D) Row(y int) []byte {
+	if y < m.Bounds.Min.Y || y >= m.Bounds.Max.Y {
+		return nil
+	}
+	return m.Data[y-m.Bounds.Min.Y]
+}
+
+// Col returns a copy of column x of the map. It returns nil if x is out of
+// bounds.
+func (m Map2D) Col(x int) []byte {
+	if x < m.Bounds.Min.X || x >= m.Bounds.Max.X {
+		return nil
+	}
+	col := make([]byte, len(m.Data))
+	for i, line := range m.Data {
+		col[i] = line[x-m.Bounds.Min.X]
+	}
+	return col
+}
+
 // All returns an iterator that iterates over all points in the map.
 func (m Map2D) All() Iter2[image.Point, byte] {
 	return m.AllWithin(m.Bounds)
@@ -208,7 +305,7 @@ func PointsIterateDelta(r image.Rectangle, delta image.Point) Iter[image.Point]
 func RectangleContainingPoints(pts []image.Point) image.Rectangle {
 	var r image.Rectangle
 	for _, pt := range pts {
-		r = r.Union(image.Rectangle{Min: pt, Max: pt})
+		r = r.Union(image.Rectangle{Min: pt, Max: pt.Add(image.Pt(1, 1))})
 	}
 	return r
 }
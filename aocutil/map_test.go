@@ -1,6 +1,8 @@
 package aocutil
 
 import (
+	"image"
+	"image/color"
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
@@ -18,3 +20,71 @@ func TestMap2D_Transpose(t *testing.T) {
 		{3, 6},
 	}), m.Transpose())
 }
+
+func TestRectangleContainingPoints(t *testing.T) {
+	r := RectangleContainingPoints([]image.Point{{-1, 2}, {3, -4}, {0, 0}})
+	assert.Equal(t, image.Rect(-1, -4, 4, 3), r)
+}
+
+func TestMap2D_Rotate(t *testing.T) {
+	m := NewMap2D("abc\ndef\n")
+	m.Bounds = m.Bounds.Add(image.Pt(5, 5))
+
+	cw := m.RotateCW()
+	assert.Equal(t, "da\neb\nfc\n", cw.String())
+	assert.Equal(t, image.Rect(5, 5, 7, 8), cw.Bounds)
+
+	ccw := m.RotateCCW()
+	assert.Equal(t, "cf\nbe\nad\n", ccw.String())
+	assert.True(t, m.Equal(cw.RotateCW().RotateCW().RotateCW()))
+	assert.True(t, m.Equal(ccw.RotateCW()))
+}
+
+func TestMap2D_Flip(t *testing.T) {
+	m := NewMap2D("abc\ndef\n")
+	assert.Equal(t, "cba\nfed\n", m.FlipH().String())
+	assert.Equal(t, "def\nabc\n", m.FlipV().String())
+	assert.Equal(t, "abc\ndef\n", m.String())
+}
+
+func TestMap2D_SubMap(t *testing.T) {
+	m := NewMap2D("abcd\nefgh\nijkl\n")
+	m.Bounds = m.Bounds.Add(image.Pt(-1, -1))
+
+	sub := m.SubMap(image.Rect(0, 0, 5, 5))
+	assert.Equal(t, image.Rect(0, 0, 3, 2), sub.Bounds)
+	assert.Equal(t, "fgh\njkl\n", sub.String())
+	assert.Equal(t, byte('f'), sub.At(image.Pt(0, 0)))
+
+	sub.Set(image.Pt(1, 1), 'X')
+	assert.Equal(t, byte('X'), m.At(image.Pt(1, 1)))
+	assert.Equal(t, "abcd\nefgh\nijXl\n", m.String())
+}
+
+func TestMap2D_RowCol(t *testing.T) {
+	m := NewMap2D("abc\ndef\n")
+	m.Bounds = m.Bounds.Add(image.Pt(1, 1))
+
+	assert.Equal(t, []byte("def"), m.Row(2))
+	assert.Equal(t, []byte("be"), m.Col(2))
+	assert.Equal(t, []byte(nil), m.Row(0))
+	assert.Equal(t, []byte(nil), m.Col(4))
+}
+
+func TestMap2D_Draw(t *testing.T) {
+	colors := map[byte]color.RGBA{
+		'c': {0, 0, 255, 255},
+		'a': {255, 0, 0, 255},
+		'b': {0, 255, 0, 255},
+	}
+	img := NewMap2D("abc\n").Draw(colors)
+
+	// The palette is sorted by byte after the background color.
+	assert.Equal(t, color.Palette{
+		color.RGBA{0, 0, 0, 255},
+		colors['a'],
+		colors['b'],
+		colors['c'],
+	}, img.Palette)
+	assert.Equal(t, []uint8{1, 2, 3}, img.Pix)
+}
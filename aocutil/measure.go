@@ -0,0 +1,80 @@
+package aocutil
+
+import (
+	"runtime"
+	"time"
+
+	"libdb.so/aoc-2023/aocutil/result"
+)
+
+// PartStats describes the resources used by a single run of a part.
+type PartStats = result.Stats
+
+// BenchStats describes the durations of repeatedly running a part.
+type BenchStats = result.Bench
+
+// heapSampleInterval is how often the heap size is sampled by Measure.
+const heapSampleInterval = 5 * time.Millisecond
+
+// Measure calls f and measures its duration and memory usage.
+func Measure(f func()) (time.Duration, PartStats) {
+	runtime.GC()
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	peak := before.HeapAlloc
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(heapSampleInterval)
+		defer ticker.Stop()
+
+		var m runtime.MemStats
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				runtime.ReadMemStats(&m)
+				peak = max(peak, m.HeapAlloc)
+			}
+		}
+	}()
+
+	start := time.Now()
+	f()
+	elapsed := time.Since(start)
+
+	close(stop)
+	<-done
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	return elapsed, PartStats{
+		Allocs:     after.Mallocs - before.Mallocs,
+		AllocBytes: after.TotalAlloc - before.TotalAlloc,
+		PeakHeap:   max(peak, after.HeapAlloc),
+	}
+}
+
+// Bench calls f n times and returns the statistics of its durations.
+func Bench(n int, f func()) BenchStats {
+	Assertf(n > 0, "Bench: n must be positive, got %d", n)
+
+	durations := make([]time.Duration, n)
+	for i := range durations {
+		start := time.Now()
+		f()
+		durations[i] = time.Since(start)
+	}
+	return result.NewBench(durations)
+}
+
+// FormatBytes formats the given number of bytes using binary prefixes.
+func FormatBytes(b uint64) string {
+	return result.FormatBytes(b)
+}
@@ -0,0 +1,39 @@
+package aocutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestBench(t *testing.T) {
+	var i int
+	stats := Bench(20, func() {
+		i++
+		time.Sleep(time.Duration(i) * 100 * time.Microsecond)
+	})
+
+	assert.Equal(t, 20, stats.Runs)
+	assert.True(t, stats.Min <= stats.Median)
+	assert.True(t, stats.Median <= stats.P95)
+	assert.True(t, stats.Min >= 100*time.Microsecond)
+}
+
+func TestMeasure(t *testing.T) {
+	var sink [][]byte
+	_, stats := Measure(func() {
+		for i := 0; i < 100; i++ {
+			sink = append(sink, make([]byte, 1024))
+		}
+	})
+	assert.True(t, stats.Allocs >= 100, "allocs = %d", stats.Allocs)
+	assert.True(t, stats.AllocBytes >= 100*1024, "alloc bytes = %d", stats.AllocBytes)
+	_ = sink
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", FormatBytes(512))
+	assert.Equal(t, "1.5 KiB", FormatBytes(1536))
+	assert.Equal(t, "3.0 MiB", FormatBytes(3<<20))
+}
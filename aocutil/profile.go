@@ -0,0 +1,96 @@
+package aocutil
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+)
+
+// profileFlags holds the file names given to the profiling flags. Empty names
+// disable the respective profile.
+type profileFlags struct {
+	cpu   string
+	mem   string
+	trace string
+	block string
+}
+
+var profiles profileFlags
+
+func (p profileFlags) enabled() bool {
+	return p.cpu != "" || p.mem != "" || p.trace != "" || p.block != ""
+}
+
+// profilePart calls f while collecting the CPU profile and execution trace
+// for the given part, if enabled. Each is written to its given file name with
+// the part label inserted before the extension, e.g. cpu.pprof becomes
+// cpu.part1.pprof.
+func profilePart(part string, f func()) {
+	if profiles.cpu == "" && profiles.trace == "" {
+		f()
+		return
+	}
+
+	if profiles.cpu != "" {
+		out := createProfile(profiles.cpu, part)
+		defer closeProfile(out)
+		E1(pprof.StartCPUProfile(out))
+		defer pprof.StopCPUProfile()
+	}
+
+	if profiles.trace != "" {
+		out := createProfile(profiles.trace, part)
+		defer closeProfile(out)
+		E1(trace.Start(out))
+		defer trace.Stop()
+	}
+
+	f()
+}
+
+// startRunProfiles starts collecting the profiles that cover the whole run.
+// The allocation and blocking profiles count from the start of the process,
+// so they cannot be split into parts. Instead, they are written once after
+// every part has run by writeRunProfiles, and include parsing. Give -1 or -2
+// to profile a single part.
+func startRunProfiles() {
+	if profiles.block != "" {
+		runtime.SetBlockProfileRate(1)
+	}
+}
+
+// writeRunProfiles writes the profiles started by startRunProfiles to their
+// given file names.
+func writeRunProfiles() {
+	if profiles.block != "" {
+		runtime.SetBlockProfileRate(0)
+		writeProfile("block", profiles.block)
+	}
+	if profiles.mem != "" {
+		// Flush the allocations made by the parts into the profile.
+		runtime.GC()
+		writeProfile("allocs", profiles.mem)
+	}
+}
+
+func writeProfile(name, file string) {
+	out := E2(os.Create(file))
+	defer closeProfile(out)
+	E1(pprof.Lookup(name).WriteTo(out, 0))
+}
+
+func createProfile(file, part string) *os.File {
+	ext := filepath.Ext(file)
+	name := fmt.Sprintf("%s.%s%s", strings.TrimSuffix(file, ext), part, ext)
+	return E2(os.Create(name))
+}
+
+func closeProfile(f *os.File) {
+	E1(f.Close())
+	log.Printf("wrote profile to %q", f.Name())
+}
@@ -0,0 +1,308 @@
+package aocutil
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// recording is set by -record.
+var recording = false
+
+// Recording returns true if -record is given, in which case SaveRecording
+// saves animations. Days can check it to skip drawing frames that would be
+// thrown away.
+func Recording() bool { return recording }
+
+// Recorder records frames of a simulation into an animated GIF or APNG.
+// Frames may have different bounds; they are laid out on a canvas that is the
+// union of all bounds, so a frame drawn from a growing Map2D stays in place.
+type Recorder struct {
+	// Delay is the delay of frames added with Add.
+	Delay time.Duration
+	// Scale upscales every frame by the given integer factor if it is more
+	// than 1.
+	Scale int
+	// Dedup drops frames that are equal to the previous frame, extending the
+	// previous frame's delay instead.
+	Dedup bool
+
+	frames []recordedFrame
+}
+
+type recordedFrame struct {
+	img   image.Image
+	delay time.Duration
+}
+
+// NewRecorder creates a new Recorder with the given delay between frames.
+func NewRecorder(delay time.Duration) *Recorder {
+	return &Recorder{Delay: delay}
+}
+
+// Add adds a frame that is shown for r.Delay.
+func (r *Recorder) Add(img image.Image) {
+	r.AddDelay(img, r.Delay)
+}
+
+// AddDelay adds a frame that is shown for the given delay.
+func (r *Recorder) AddDelay(img image.Image, delay time.Duration) {
+	if r.Dedup && len(r.frames) > 0 {
+		last := &r.frames[len(r.frames)-1]
+		if imagesEqual(last.img, img) {
+			last.delay += delay
+			return
+		}
+	}
+	r.frames = append(r.frames, recordedFrame{img: cloneImage(img), delay: delay})
+}
+
+// Len returns the number of recorded frames.
+func (r *Recorder) Len() int {
+	return len(r.frames)
+}
+
+// Save saves the animation to dst. The format is chosen by its extension:
+// .gif for GIF, or .png or .apng for APNG.
+func (r *Recorder) Save(dst string) {
+	var encode func(io.Writer) error
+	switch ext := filepath.Ext(dst); ext {
+	case ".gif":
+		encode = r.EncodeGIF
+	case ".png", ".apng":
+		encode = r.EncodeAPNG
+	default:
+		panic(fmt.Errorf("invalid extension %q, only .gif, .png and .apng are supported", ext))
+	}
+
+	f := E2(os.Create(dst))
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	E1(encode(w))
+	E1(w.Flush())
+}
+
+// SaveRecording saves the animation into the image directory under the given
+// name and logs its path if -record is given. Otherwise, it does nothing, so
+// that solving a day never writes files on its own.
+func (r *Recorder) SaveRecording(name string) {
+	if !recording {
+		return
+	}
+
+	E1(os.MkdirAll(imageDir, 0755))
+	dst := filepath.Join(imageDir, name)
+	r.Save(dst)
+
+	// The path is the only way to find the animation, so it is logged even
+	// if logging is silenced by -s.
+	slog.Warn("saved recording", "path", dst)
+}
+
+// canvas returns the union of the bounds of all frames after scaling.
+func (r *Recorder) canvas() image.Rectangle {
+	var canvas image.Rectangle
+	for i, frame := range r.frames {
+		b := r.scaled(frame.img).Bounds()
+		if i == 0 {
+			canvas = b
+		} else {
+			canvas = canvas.Union(b)
+		}
+	}
+	return canvas
+}
+
+func (r *Recorder) scaled(img image.Image) image.Image {
+	if r.Scale > 1 {
+		return ScaleImage(img, r.Scale)
+	}
+	return img
+}
+
+// EncodeGIF writes the animation as a looping GIF. Frames that are not
+// paletted are converted to the Plan 9 palette.
+func (r *Recorder) EncodeGIF(w io.Writer) error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames recorded")
+	}
+
+	canvas := r.canvas()
+	anim := gif.GIF{
+		Config: image.Config{
+			Width:  canvas.Dx(),
+			Height: canvas.Dy(),
+		},
+	}
+
+	for _, frame := range r.frames {
+		img := r.scaled(frame.img)
+		bounds := img.Bounds().Sub(canvas.Min)
+
+		paletted, ok := img.(*image.Paletted)
+		if ok && len(paletted.Palette) <= 256 {
+			paletted = &image.Paletted{
+				Pix:     paletted.Pix,
+				Stride:  paletted.Stride,
+				Rect:    bounds,
+				Palette: paletted.Palette,
+			}
+		} else {
+			paletted = image.NewPaletted(bounds, palette.Plan9)
+			draw.Draw(paletted, bounds, img, img.Bounds().Min, draw.Src)
+		}
+		if anim.Config.ColorModel == nil {
+			anim.Config.ColorModel = paletted.Palette
+		}
+
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, int(frame.delay/(10*time.Millisecond)))
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+
+	return gif.EncodeAll(w, &anim)
+}
+
+// EncodeAPNG writes the animation as a looping APNG. Viewers that do not
+// support APNG show the first frame.
+func (r *Recorder) EncodeAPNG(w io.Writer) error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames recorded")
+	}
+
+	canvas := r.canvas()
+	pw := &pngWriter{w: w}
+
+	pw.write([]byte("\x89PNG\r\n\x1a\n"))
+
+	var ihdr [13]byte
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(canvas.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(canvas.Dy()))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 6 // RGBA
+	pw.chunk("IHDR", ihdr[:])
+
+	var actl [8]byte
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(r.frames)))
+	binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+	pw.chunk("acTL", actl[:])
+
+	var seq uint32
+	for i, frame := range r.frames {
+		img := r.scaled(frame.img)
+		// Every frame covers the whole canvas, so that all frames are encoded
+		// the same way as the default image.
+		rgba := image.NewNRGBA(image.Rectangle{Max: canvas.Size()})
+		draw.Draw(rgba, img.Bounds().Sub(canvas.Min), img, img.Bounds().Min, draw.Src)
+
+		var fctl [26]byte
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(canvas.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(canvas.Dy()))
+		// x and y offsets are 0.
+		binary.BigEndian.PutUint16(fctl[20:], uint16(min(frame.delay.Milliseconds(), 0xFFFF)))
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		// dispose_op and blend_op are 0, which replace the whole canvas.
+		pw.chunk("fcTL", fctl[:])
+		seq++
+
+		data := encodePNGData(rgba)
+		if i == 0 {
+			pw.chunk("IDAT", data)
+		} else {
+			fdat := binary.BigEndian.AppendUint32(nil, seq)
+			pw.chunk("fdAT", append(fdat, data...))
+			seq++
+		}
+	}
+
+	pw.chunk("IEND", nil)
+	return pw.err
+}
+
+type pngWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (pw *pngWriter) write(b []byte) {
+	if pw.err == nil {
+		_, pw.err = pw.w.Write(b)
+	}
+}
+
+func (pw *pngWriter) chunk(name string, data []byte) {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], name)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	pw.write(header[:])
+	pw.write(data)
+	pw.write(binary.BigEndian.AppendUint32(nil, crc.Sum32()))
+}
+
+// encodePNGData compresses the rows of img without filtering.
+func encodePNGData(img *image.NRGBA) []byte {
+	var buf bytes.Buffer
+	z := zlib.NewWriter(&buf)
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		z.Write([]byte{0})
+		z.Write(row)
+	}
+	z.Close()
+	return buf.Bytes()
+}
+
+// cloneImage copies img so that the recorded frame is not changed by later
+// drawing into the same image.
+func cloneImage(img image.Image) image.Image {
+	switch img := img.(type) {
+	case *image.Paletted:
+		clone := *img
+		clone.Pix = bytes.Clone(img.Pix)
+		return &clone
+	default:
+		clone := image.NewNRGBA(img.Bounds())
+		draw.Draw(clone, clone.Rect, img, img.Bounds().Min, draw.Src)
+		return clone
+	}
+}
+
+// imagesEqual returns true if the two images have the same bounds and
+// colors.
+func imagesEqual(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	for pt := range PointsWithin(a.Bounds()) {
+		if !colorsEqual(a.At(pt.X, pt.Y), b.At(pt.X, pt.Y)) {
+			return false
+		}
+	}
+	return true
+}
+
+func colorsEqual(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
@@ -0,0 +1,80 @@
+package aocutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+var recordColors = map[byte]color.RGBA{
+	'.': {0, 0, 0, 255},
+	'#': {255, 255, 255, 255},
+}
+
+func recordFrames() *Recorder {
+	r := NewRecorder(100 * time.Millisecond)
+	r.Dedup = true
+	r.Scale = 2
+
+	m := NewMap2D("#.\n.#\n")
+	r.Add(m.Draw(recordColors))
+	r.Add(m.Draw(recordColors)) // deduplicated
+	m.Set(image.Pt(1, 0), '#')
+	r.Add(m.Draw(recordColors))
+
+	// A larger frame that grows the canvas to the left and top.
+	grown := NewEmptyMap2D(image.Rect(-1, -1, 2, 2))
+	grown.Set(image.Pt(-1, -1), '#')
+	r.AddDelay(grown.Draw(recordColors), time.Second)
+
+	return r
+}
+
+func TestRecorderGIF(t *testing.T) {
+	r := recordFrames()
+	assert.Equal(t, 3, r.Len())
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.EncodeGIF(&buf))
+
+	anim, err := gif.DecodeAll(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{20, 10, 100}, anim.Delay)
+	assert.Equal(t, image.Config{Width: 6, Height: 6, ColorModel: anim.Config.ColorModel}, anim.Config)
+	assert.Equal(t, image.Rect(2, 2, 6, 6), anim.Image[0].Bounds())
+	assert.Equal(t, image.Rect(0, 0, 6, 6), anim.Image[2].Bounds())
+}
+
+func TestRecorderAPNG(t *testing.T) {
+	r := recordFrames()
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.EncodeAPNG(&buf))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("acTL")))
+	assert.Equal(t, 3, bytes.Count(buf.Bytes(), []byte("fcTL")))
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("fdAT")))
+
+	// Decoders without APNG support see the first frame.
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 6, 6), img.Bounds())
+	assert.True(t, colorsEqual(color.White, img.At(2, 3)))
+	assert.True(t, colorsEqual(color.Black, img.At(4, 3)))
+	assert.True(t, colorsEqual(color.Transparent, img.At(0, 0)))
+}
+
+func TestScaleImage(t *testing.T) {
+	m := NewEmptyMap2D(image.Rect(-1, 0, 1, 1))
+	m.Set(image.Pt(-1, 0), '#')
+
+	img := ScaleImage(m.Draw(recordColors), 3)
+	assert.Equal(t, image.Rect(-3, 0, 3, 3), img.Bounds())
+	assert.True(t, colorsEqual(color.White, img.At(-1, 2)))
+	assert.True(t, colorsEqual(color.Black, img.At(0, 2)))
+}
@@ -0,0 +1,111 @@
+// Package result describes the results of running the parts of a day. Days
+// print them as JSON lines with -json, and the aoc command reads them back.
+//
+// Unlike aocutil, this package registers no flags, so commands can import it.
+package result
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Part is the result of running a single part of a day.
+type Part struct {
+	Part     int           `json:"part"`
+	Answer   string        `json:"answer"`
+	Duration time.Duration `json:"duration"`
+	// Input is the name of the input file that the part ran on. It is empty
+	// if the input was read from stdin and does not match any input file.
+	Input string `json:"input,omitempty"`
+	// Status is the verification status of the answer. It is empty if there
+	// are no answers to verify against.
+	Status Status `json:"status,omitempty"`
+	// Parse is the time it took to parse the input given to the part. It is
+	// only set if -time is given and the day is run using ParseAndRun.
+	Parse time.Duration `json:"parse,omitempty"`
+	// Stats is only set if -time is given.
+	Stats *Stats `json:"stats,omitempty"`
+	// Bench is only set if -bench is given.
+	Bench *Bench `json:"bench,omitempty"`
+	// Mutated reports whether the part changed its parsed input. It is only
+	// set if -race-check is given and the day uses ParseAndRun, since parts
+	// given to Run only get an immutable string.
+	Mutated *bool `json:"mutated,omitempty"`
+	// TimedOut is true if the part did not finish within -timeout. Answer is
+	// empty in that case.
+	TimedOut bool `json:"timed_out,omitempty"`
+	// Scope is the log scope of the last record that the part logged before it
+	// timed out.
+	Scope string `json:"scope,omitempty"`
+}
+
+// Status is the result of verifying an answer.
+type Status string
+
+const (
+	// Unknown means that there is no expected answer to compare to.
+	Unknown Status = "UNKNOWN"
+	// Pass means that the answer matches the expected answer.
+	Pass Status = "PASS"
+	// Fail means that the answer does not match the expected answer.
+	Fail Status = "FAIL"
+)
+
+// Stats describes the resources used by a single run of a part.
+type Stats struct {
+	// Allocs is the number of heap allocations.
+	Allocs uint64 `json:"allocs"`
+	// AllocBytes is the total number of bytes allocated on the heap.
+	AllocBytes uint64 `json:"alloc_bytes"`
+	// PeakHeap is the highest observed heap size while the part was running.
+	// It is sampled, so very short spikes may be missed.
+	PeakHeap uint64 `json:"peak_heap"`
+}
+
+// String formats the stats for humans.
+func (s Stats) String() string {
+	return fmt.Sprintf("%d allocs, %s allocated, %s peak heap",
+		s.Allocs, FormatBytes(s.AllocBytes), FormatBytes(s.PeakHeap))
+}
+
+// Bench describes the durations of repeatedly running a part.
+type Bench struct {
+	Runs   int           `json:"runs"`
+	Min    time.Duration `json:"min"`
+	Median time.Duration `json:"median"`
+	P95    time.Duration `json:"p95"`
+}
+
+// NewBench computes the statistics of the given durations. The durations are
+// sorted in place.
+func NewBench(durations []time.Duration) Bench {
+	slices.Sort(durations)
+	n := len(durations)
+	return Bench{
+		Runs:   n,
+		Min:    durations[0],
+		Median: durations[n/2],
+		P95:    durations[(n*95-1)/100],
+	}
+}
+
+// String formats the stats for humans.
+func (s Bench) String() string {
+	return fmt.Sprintf("%d runs: min %v, median %v, p95 %v",
+		s.Runs, s.Min, s.Median, s.P95)
+}
+
+// FormatBytes formats the given number of bytes using binary prefixes.
+func FormatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,397 @@
+package aocutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/aoc-2023/aocutil/result"
+)
+
+// PartResult is the result of running a single part of a day.
+type PartResult = result.Part
+
+// resultLabel returns a short human-readable label for the result, including
+// the input name if the inputs were chosen using flags.
+func resultLabel(r PartResult) string {
+	if r.Input != "" && inputsFromFlags() {
+		return fmt.Sprintf("%s part %d", r.Input, r.Part)
+	}
+	return fmt.Sprintf("part %d", r.Part)
+}
+
+// Run runs the given functions with the stdin input. The answers may be of
+// any type supported by FormatAnswer, and each part may return a different
+// type. Both parts run concurrently unless -seq is given.
+func Run[A1, A2 any](p1 func(string) A1, p2 func(string) A2) {
+	RunContext(ignoreContext(p1), ignoreContext(p2))
+}
+
+// RunContext is like Run, except the parts are given a context that is done
+// once they time out. A part that returns after its context is done is
+// reported as timed out, so it may stop early with any answer.
+func RunContext[A1, A2 any](p1 func(context.Context, string) A1, p2 func(context.Context, string) A2) {
+	if raceCheck {
+		slog.Warn("-race-check is not supported by days using Run, since their input cannot be mutated")
+	}
+
+	startRunProfiles()
+	var failed bool
+	for _, input := range readInputs() {
+		failed = runParts(input,
+			rawPart(input.Data, p1),
+			rawPart(input.Data, p2),
+		) || failed
+	}
+	writeRunProfiles()
+	exitIfFailed(failed)
+}
+
+// ParseAndRun runs the given functions with the input after parsing it. Each
+// part is given its own freshly parsed value, so parts are free to mutate it.
+// Both parts run concurrently unless -seq is given.
+func ParseAndRun[T, A1, A2 any](parse func(string) T, p1 func(T) A1, p2 func(T) A2) {
+	ParseAndRunContext(parse, ignoreContext(p1), ignoreContext(p2))
+}
+
+// ParseAndRunContext is like ParseAndRun, except the parts are given a context
+// that is done once they time out, as with RunContext.
+func ParseAndRunContext[T, A1, A2 any](parse func(string) T, p1 func(context.Context, T) A1, p2 func(context.Context, T) A2) {
+	startRunProfiles()
+	var failed bool
+	for _, input := range readInputs() {
+		failed = runParts(input,
+			parsedPart(input.Data, parse, p1),
+			parsedPart(input.Data, parse, p2),
+		) || failed
+	}
+	writeRunProfiles()
+	exitIfFailed(failed)
+}
+
+func ignoreContext[T, A any](part func(T) A) func(context.Context, T) A {
+	return func(_ context.Context, v T) A { return part(v) }
+}
+
+// preparedPart prepares the input of a part and returns the function that runs
+// the part on it. It is called again for every run of the part. mutated is
+// nil if the part cannot mutate its input, otherwise it reports whether the
+// last run changed the prepared input.
+type preparedPart func() (run func(context.Context) string, mutated func() bool)
+
+func rawPart[A any](input string, part func(context.Context, string) A) preparedPart {
+	return func() (func(context.Context) string, func() bool) {
+		run := func(ctx context.Context) string { return FormatAnswer(part(ctx, input)) }
+		return run, nil
+	}
+}
+
+func parsedPart[T, A any](input string, parse func(string) T, part func(context.Context, T) A) preparedPart {
+	return func() (func(context.Context) string, func() bool) {
+		value := parse(input)
+		run := func(ctx context.Context) string { return FormatAnswer(part(ctx, value)) }
+		mutated := func() bool { return !reflect.DeepEqual(value, parse(input)) }
+		return run, mutated
+	}
+}
+
+// FormatAnswer formats the answer of a part. Answers may be strings, integers
+// of any size, *big.Int or any fmt.Stringer. Any other type panics, since
+// it is most likely a mistake, e.g. a float64 that would be printed in
+// scientific notation.
+func FormatAnswer(answer any) string {
+	switch a := answer.(type) {
+	case string:
+		return a
+	case fmt.Stringer:
+		// This also covers *big.Int.
+		return a.String()
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr:
+		return fmt.Sprint(a)
+	}
+
+	rv := reflect.ValueOf(answer)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	}
+
+	panic(fmt.Sprintf("unsupported answer type %T", answer))
+}
+
+// namedInput is an input along with the name of the file it was read from.
+type namedInput struct {
+	Name string
+	Data string
+}
+
+func inputsFromFlags() bool {
+	return len(inputNames) > 0 || allInputs
+}
+
+// readInputs reads the inputs chosen by the -i and -all-inputs flags. If
+// neither is given, the input is read from stdin.
+func readInputs() []namedInput {
+	if !inputsFromFlags() {
+		data := ReadStdin()
+		return []namedInput{{IdentifyInput(".", data), data}}
+	}
+
+	names := []string(inputNames)
+	if allInputs {
+		names = InputFiles(".")
+		Assertf(len(names) > 0, "no input files found in the working directory")
+	}
+
+	inputs := make([]namedInput, len(names))
+	for i, name := range names {
+		inputs[i] = namedInput{filepath.Base(name), ReadFile(name)}
+	}
+	return inputs
+}
+
+// runParts runs the parts on the given input and prints their results in
+// order. True is returned if any part gave a wrong answer or timed out.
+func runParts(input namedInput, parts ...preparedPart) (failed bool) {
+	answers := E2(LoadAnswers(answersPath))
+
+	results := make([]chan PartResult, len(parts))
+	for i := range parts {
+		if shouldRunPart(i + 1) {
+			results[i] = make(chan PartResult, 1)
+		}
+	}
+
+	if runSequentially() {
+		for i, part := range parts {
+			if results[i] == nil {
+				continue
+			}
+			ctx, cancel := newPartContext()
+			results[i] <- runPart(ctx, input, i+1, part)
+			cancel()
+		}
+	} else {
+		// Concurrent parts share a single context, since they start at the
+		// same time and therefore time out at the same time.
+		ctx, cancel := newPartContext()
+		defer cancel()
+
+		for i, part := range parts {
+			if results[i] == nil {
+				continue
+			}
+			go func(ch chan<- PartResult, n int, part preparedPart) {
+				ch <- runPart(ctx, input, n, part)
+			}(results[i], i+1, part)
+		}
+	}
+
+	for _, ch := range results {
+		if ch == nil {
+			continue
+		}
+
+		result := <-ch
+		if answers != nil && !result.TimedOut {
+			result.Status = answers.Verify(input.Name, result.Part, result.Answer)
+		}
+		failed = failed || result.Status == AnswerFail || result.TimedOut
+		printResult(result)
+	}
+
+	return failed
+}
+
+// newPartContext creates the context for running parts. It times out after
+// -timeout, if given.
+func newPartContext() (context.Context, context.CancelFunc) {
+	if partTimeout > 0 {
+		return context.WithTimeout(context.Background(), partTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// runSequentially returns true if the parts must not run concurrently, which
+// is the case if -seq is given, if anything process-wide is measured or if the
+// parts are stepped through.
+func runSequentially() bool {
+	return sequential || timeParts || benchRuns > 0 || profiles.enabled() || IsStepping()
+}
+
+// cancelGracePeriod is how long a part is given to return after its context
+// is done before it is reported as timed out regardless. Parts that never
+// check their context are abandoned and killed when the process exits.
+const cancelGracePeriod = 100 * time.Millisecond
+
+// runPart runs the nth part on the input.
+func runPart(ctx context.Context, input namedInput, n int, part preparedPart) PartResult {
+	result := PartResult{
+		Part:  n,
+		Input: input.Name,
+	}
+
+	profileLabel := fmt.Sprintf("part%d", n)
+	if inputsFromFlags() {
+		profileLabel = input.Name + "." + profileLabel
+	}
+
+	parseStart := time.Now()
+	run, mutated := part()
+	parseTime := time.Since(parseStart)
+
+	// Everything written by the goroutine must only be read after done is
+	// closed, since the goroutine may outlive this function if the part
+	// times out.
+	var (
+		answer  string
+		elapsed time.Duration
+		stats   PartStats
+	)
+	ctx, lastScope := trackLogScope(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		call := func() { profilePart(profileLabel, func() { answer = run(ctx) }) }
+		// Measure forces a garbage collection and keeps stopping the world
+		// to sample the heap, so it is only worth it if -time is given.
+		if timeParts {
+			elapsed, stats = Measure(call)
+		} else {
+			start := time.Now()
+			call()
+			elapsed = time.Since(start)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		scope := LogScope(nil)
+		if last := lastScope.Load(); last != nil {
+			scope = *last
+		}
+		result.Scope = strings.TrimSuffix(scope, ": ")
+		select {
+		case <-done:
+		case <-time.After(cancelGracePeriod):
+			result.TimedOut = true
+			result.Duration = partTimeout
+			return result
+		}
+	}
+
+	if ctx.Err() != nil {
+		result.TimedOut = true
+		result.Duration = partTimeout
+		return result
+	}
+	result.Scope = ""
+
+	result.Answer = answer
+	result.Duration = elapsed
+
+	// Only parsed parts have an input that can be mutated and a parse time
+	// worth reporting.
+	if raceCheck && mutated != nil {
+		m := mutated()
+		result.Mutated = &m
+	}
+	if timeParts {
+		if mutated != nil {
+			result.Parse = parseTime
+		}
+		result.Stats = &stats
+	}
+	if benchRuns > 0 {
+		withLoggingSilenced(func() {
+			bench := benchPart(benchRuns, part)
+			result.Bench = &bench
+		})
+	}
+
+	return result
+}
+
+// benchPart benchmarks the part, excluding the time spent preparing its input.
+func benchPart(n int, part preparedPart) BenchStats {
+	durations := make([]time.Duration, n)
+	for i := range durations {
+		run, _ := part()
+		start := time.Now()
+		run(context.Background())
+		durations[i] = time.Since(start)
+	}
+	return result.NewBench(durations)
+}
+
+func exitIfFailed(failed bool) {
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func shouldRunPart(n int) bool {
+	switch {
+	case part1Only:
+		return n == 1
+	case part2Only:
+		return n == 2
+	default:
+		return true
+	}
+}
+
+func printResult(r PartResult) {
+	if jsonOutput {
+		E1(json.NewEncoder(os.Stdout).Encode(r))
+		return
+	}
+
+	switch {
+	case r.TimedOut:
+		// There is no answer to print.
+	case inputsFromFlags():
+		fmt.Printf("%s: %s\n", resultLabel(r), r.Answer)
+	default:
+		fmt.Println(r.Answer)
+	}
+
+	// Everything else goes to stderr so that stdout only has the answers.
+	// Inputs without a recorded answer are common enough that saying so is
+	// only noise.
+	if r.Status != "" && r.Status != AnswerUnknown {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", resultLabel(r), r.Status)
+	}
+	if r.Stats != nil {
+		fmt.Fprintf(os.Stderr, "%s: took %v", resultLabel(r), r.Duration)
+		if r.Parse > 0 {
+			fmt.Fprintf(os.Stderr, " (parse %v)", r.Parse)
+		}
+		fmt.Fprintf(os.Stderr, ", %v\n", r.Stats)
+	}
+	if r.Bench != nil {
+		fmt.Fprintf(os.Stderr, "%s: bench %v\n", resultLabel(r), r.Bench)
+	}
+	if r.TimedOut {
+		fmt.Fprintf(os.Stderr, "%s: timed out after %v\n", resultLabel(r), r.Duration)
+		if r.Scope != "" {
+			fmt.Fprintf(os.Stderr, "%s: last logged in scope %q\n", resultLabel(r), r.Scope)
+		}
+	}
+	if r.Mutated != nil && *r.Mutated {
+		fmt.Fprintf(os.Stderr, "%s: mutated its parsed input\n", resultLabel(r))
+	}
+}
@@ -0,0 +1,27 @@
+package aocutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+type testDirection int
+
+func (d testDirection) String() string { return [...]string{"up", "down"}[d] }
+
+type testLetters string
+
+func TestFormatAnswer(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	assert.Equal(t, "42", FormatAnswer(42))
+	assert.Equal(t, "-42", FormatAnswer(int8(-42)))
+	assert.Equal(t, "18446744073709551615", FormatAnswer(uint64(1<<64-1)))
+	assert.Equal(t, "EFEHJLJB", FormatAnswer("EFEHJLJB"))
+	assert.Equal(t, "ABC", FormatAnswer(testLetters("ABC")))
+	assert.Equal(t, "down", FormatAnswer(testDirection(1)))
+	assert.Equal(t, "123456789012345678901234567890", FormatAnswer(huge))
+	assert.Panics(t, func() { FormatAnswer(1.5) })
+}
@@ -0,0 +1,236 @@
+package aocutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var stepping = false
+
+// steps is the stepper used by Checkpoint. It is only active if -step is
+// given and stdin is a terminal.
+var steps = newStepper(os.Stdin, os.Stderr)
+
+// Checkpoint marks a point in the solution where the -step debugger pauses.
+// Checkpoints are identified by their name, e.g. "tilt" or "tick". It does
+// nothing unless stepping is enabled, so it can be called in hot loops.
+func Checkpoint(name string) {
+	if steps.active.Load() {
+		steps.checkpoint(name)
+	}
+}
+
+// IsStepping returns true if the -step debugger is active.
+func IsStepping() bool { return steps.active.Load() }
+
+// WatchState registers a state that is printed by the debugger's print
+// command. The value returned by f is formatted with fmt.Sprint, so it may be
+// a Map2D or anything else with a String method. Call unwatch once the state
+// is no longer valid.
+func WatchState(name string, f func() any) (unwatch func()) {
+	return steps.watch(&watched{name: name, state: f})
+}
+
+// WatchCondition registers a condition that the debugger can run until, e.g.
+// "until cycle". Conditions are only checked at checkpoints.
+func WatchCondition(name string, f func() bool) (unwatch func()) {
+	return steps.watch(&watched{name: name, cond: f})
+}
+
+type watched struct {
+	name  string
+	state func() any
+	cond  func() bool
+}
+
+type stepper struct {
+	active atomic.Bool
+
+	mu  sync.Mutex
+	in  *bufio.Scanner
+	out io.Writer
+
+	hits    map[string]int
+	watches []*watched
+
+	// skip is the number of checkpoints left to run through before pausing.
+	skip int
+	// until is the name of the checkpoint or condition to run until.
+	until string
+}
+
+func newStepper(in io.Reader, out io.Writer) *stepper {
+	return &stepper{
+		in:   bufio.NewScanner(in),
+		out:  out,
+		hits: make(map[string]int),
+	}
+}
+
+func (s *stepper) watch(w *watched) (unwatch func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.watches = append(s.watches, w)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.watches = slices.DeleteFunc(s.watches, func(w2 *watched) bool { return w2 == w })
+	}
+}
+
+func (s *stepper) lookup(name string, isCond bool) *watched {
+	for _, w := range s.watches {
+		if w.name == name && (w.cond != nil) == isCond {
+			return w
+		}
+	}
+	return nil
+}
+
+func (s *stepper) checkpoint(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hits[name]++
+	if s.shouldPause(name) {
+		s.prompt(name)
+	}
+}
+
+func (s *stepper) shouldPause(name string) bool {
+	switch {
+	case s.skip > 0:
+		s.skip--
+		return false
+	case s.until != "":
+		if name == s.until {
+			s.until = ""
+			return true
+		}
+		if w := s.lookup(s.until, true); w != nil && w.cond() {
+			s.until = ""
+			return true
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+const stepperHelp = `commands:
+  s, <enter>   step to the next checkpoint
+  c [N]        continue N checkpoints, or until the end if N is not given
+  u NAME       run until the checkpoint or condition NAME
+  p [NAME...]  print the watched states, or only the given ones
+  l            list the checkpoints, states and conditions
+  h            show this help
+`
+
+// prompt reads commands until one resumes the solution. If stdin is closed,
+// then stepping is disabled.
+func (s *stepper) prompt(name string) {
+	for {
+		fmt.Fprintf(s.out, "%s #%d> ", name, s.hits[name])
+		if !s.in.Scan() {
+			fmt.Fprintln(s.out)
+			s.active.Store(false)
+			return
+		}
+
+		cmd, args, _ := strings.Cut(strings.TrimSpace(s.in.Text()), " ")
+		args = strings.TrimSpace(args)
+
+		switch cmd {
+		case "", "s":
+			return
+		case "c":
+			if args == "" {
+				s.active.Store(false)
+				return
+			}
+			n, err := strconv.Atoi(args)
+			if err != nil || n < 1 {
+				fmt.Fprintf(s.out, "invalid number of checkpoints %q\n", args)
+				continue
+			}
+			s.skip = n - 1
+			return
+		case "u":
+			if args == "" {
+				fmt.Fprintln(s.out, "missing checkpoint or condition name")
+				continue
+			}
+			s.until = args
+			return
+		case "p":
+			s.printStates(strings.Fields(args))
+		case "l":
+			s.list()
+		case "h", "?":
+			io.WriteString(s.out, stepperHelp)
+		default:
+			fmt.Fprintf(s.out, "unknown command %q, try h\n", cmd)
+		}
+	}
+}
+
+func (s *stepper) printStates(names []string) {
+	for _, w := range s.watches {
+		if w.state == nil || (len(names) > 0 && !slices.Contains(names, w.name)) {
+			continue
+		}
+		v := fmt.Sprint(w.state())
+		if strings.Contains(v, "\n") {
+			fmt.Fprintf(s.out, "%s:\n%s\n", w.name, strings.TrimSuffix(v, "\n"))
+		} else {
+			fmt.Fprintf(s.out, "%s: %s\n", w.name, v)
+		}
+	}
+}
+
+func (s *stepper) list() {
+	names := make([]string, 0, len(s.hits))
+	for name := range s.hits {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		fmt.Fprintf(s.out, "checkpoint %s: hit %d times\n", name, s.hits[name])
+	}
+	for _, w := range s.watches {
+		if w.cond != nil {
+			fmt.Fprintf(s.out, "condition %s: %v\n", w.name, w.cond())
+		} else {
+			fmt.Fprintf(s.out, "state %s\n", w.name)
+		}
+	}
+}
+
+// stdinIsTerminal returns true if stdin is a terminal.
+func stdinIsTerminal() bool {
+	return isTerminal(os.Stdin)
+}
+
+// isTerminal returns true if w is a file that is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// /dev/null is also a character device.
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(stat, null)
+}
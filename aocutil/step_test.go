@@ -0,0 +1,58 @@
+package aocutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestStepper(t *testing.T) {
+	var out strings.Builder
+	s := newStepper(strings.NewReader(strings.Join([]string{
+		"",         // tick #1: step
+		"p",        // tick #2: print
+		"c 2",      // tick #2: skip tick #3
+		"u done",   // tick #4: run until the condition holds
+		"u finish", // tick #6: run until the checkpoint
+		"c",        // finish #1: stop stepping
+	}, "\n")), &out)
+	s.active.Store(true)
+
+	checkpoint := func(name string) {
+		if s.active.Load() {
+			s.checkpoint(name)
+		}
+	}
+
+	var i int
+	s.watch(&watched{name: "i", state: func() any { return i }})
+	s.watch(&watched{name: "done", cond: func() bool { return i == 6 }})
+
+	for i = 1; i <= 8; i++ {
+		checkpoint("tick")
+	}
+	checkpoint("finish")
+	checkpoint("finish")
+
+	assert.Equal(t, ""+
+		"tick #1> "+
+		"tick #2> i: 2\n"+
+		"tick #2> "+
+		"tick #4> "+
+		"tick #6> "+
+		"finish #1> ",
+		out.String())
+	assert.False(t, s.active.Load())
+	assert.Equal(t, 8, s.hits["tick"])
+	assert.Equal(t, 1, s.hits["finish"])
+}
+
+func TestStepperEOF(t *testing.T) {
+	var out strings.Builder
+	s := newStepper(strings.NewReader(""), &out)
+	s.active.Store(true)
+
+	s.checkpoint("tick")
+	assert.False(t, s.active.Load())
+}
@@ -0,0 +1,141 @@
+package submit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"time"
+)
+
+// HistoryFile is the default name of the per-day guess history file. It lives
+// next to the input files.
+const HistoryFile = "guesses"
+
+// Guess is a single submitted answer.
+type Guess struct {
+	Part    int       `json:"part"`
+	Answer  string    `json:"answer"`
+	Verdict Verdict   `json:"verdict"`
+	Time    time.Time `json:"time"`
+}
+
+// History is the history of guesses of a single day. It is stored as JSON
+// lines so that appending a guess never rewrites the file.
+type History struct {
+	Path    string
+	Guesses []Guess
+}
+
+// LoadHistory loads the history at the given path. A missing file is an empty
+// history.
+func LoadHistory(path string) (*History, error) {
+	h := &History{Path: path}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return h, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		var g Guess
+		if err := json.Unmarshal(scanner.Bytes(), &g); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		h.Guesses = append(h.Guesses, g)
+	}
+
+	return h, scanner.Err()
+}
+
+// Record appends the guess to the history and its file.
+func (h *History) Record(g Guess) error {
+	b, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(h.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	h.Guesses = append(h.Guesses, g)
+	return nil
+}
+
+// ErrRefused is wrapped by the errors returned by Check.
+var ErrRefused = errors.New("refusing to submit")
+
+// Check returns an error wrapping ErrRefused if the answer of the given part
+// is already known to be wrong, either because it was guessed before or
+// because it lies outside the bounds given by earlier "too high" and "too
+// low" verdicts. It also refuses to submit a part that was already solved.
+func (h *History) Check(part int, answer string) error {
+	lower, upper := h.Bounds(part)
+
+	value, isNumber := new(big.Int).SetString(answer, 10)
+	for _, g := range h.Guesses {
+		if g.Part != part {
+			continue
+		}
+		if g.Verdict == Correct {
+			return fmt.Errorf("%w: part %d was already solved with %s", ErrRefused, part, g.Answer)
+		}
+		if g.Answer == answer && g.Verdict.IsWrong() {
+			return fmt.Errorf("%w: %s was already guessed and is %s", ErrRefused, answer, g.Verdict)
+		}
+	}
+
+	if isNumber {
+		if lower != nil && value.Cmp(lower) <= 0 {
+			return fmt.Errorf("%w: %s is too low, must be greater than %s", ErrRefused, answer, lower)
+		}
+		if upper != nil && value.Cmp(upper) >= 0 {
+			return fmt.Errorf("%w: %s is too high, must be less than %s", ErrRefused, answer, upper)
+		}
+	}
+
+	return nil
+}
+
+// Bounds returns the exclusive bounds of the answer of the given part, as
+// learned from earlier "too low" and "too high" verdicts. A nil bound is
+// unknown.
+func (h *History) Bounds(part int) (lower, upper *big.Int) {
+	for _, g := range h.Guesses {
+		if g.Part != part {
+			continue
+		}
+
+		v, ok := new(big.Int).SetString(g.Answer, 10)
+		if !ok {
+			continue
+		}
+
+		switch g.Verdict {
+		case TooLow:
+			if lower == nil || v.Cmp(lower) > 0 {
+				lower = v
+			}
+		case TooHigh:
+			if upper == nil || v.Cmp(upper) < 0 {
+				upper = v
+			}
+		}
+	}
+	return lower, upper
+}
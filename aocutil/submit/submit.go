@@ -0,0 +1,144 @@
+// Package submit submits answers and keeps a local history of every guess so
+// that known-wrong answers are never submitted twice.
+package submit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/aoc-2023/aocutil/fetch"
+)
+
+// Verdict is the server's verdict on a submitted answer.
+type Verdict string
+
+const (
+	Correct Verdict = "correct"
+	Wrong   Verdict = "wrong"
+	TooHigh Verdict = "too high"
+	TooLow  Verdict = "too low"
+	// RateLimited means that the answer was not checked because another
+	// answer was submitted too recently.
+	RateLimited Verdict = "rate limited"
+	// WrongLevel means that the part is either locked or already solved.
+	WrongLevel Verdict = "wrong level"
+	Unknown    Verdict = "unknown"
+)
+
+// IsWrong returns true if the answer was checked and is wrong.
+func (v Verdict) IsWrong() bool {
+	return v == Wrong || v == TooHigh || v == TooLow
+}
+
+// Response is the parsed response to a submission.
+type Response struct {
+	Verdict Verdict
+	// Wait is how long to wait before submitting again. It is only set if
+	// Verdict is RateLimited or a wrong answer.
+	Wait time.Duration
+	// Message is the text of the response, without any markup.
+	Message string
+}
+
+// Submit submits the answer of the given day and part.
+func Submit(ctx context.Context, client *fetch.Client, day, part int, answer string) (Response, error) {
+	u := fmt.Sprintf("%s/%d/day/%d/answer", strings.TrimSuffix(client.BaseURL, "/"), client.Year, day)
+	form := url.Values{
+		"level":  {strconv.Itoa(part)},
+		"answer": {answer},
+	}
+
+	req, err := client.NewRequest(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Response{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Response{}, fmt.Errorf("cannot submit answer: %s", resp.Status)
+	}
+
+	return ParseResponse(body), nil
+}
+
+var (
+	articleRe = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRe     = regexp.MustCompile(`<[^>]*>`)
+	spaceRe   = regexp.MustCompile(`\s+`)
+	waitRe    = regexp.MustCompile(`(?:(\d+)h )?(?:(\d+)m )?(\d+)s left to wait|wait (one|\d+) minutes?`)
+)
+
+// ParseResponse parses the HTML page returned after submitting an answer.
+func ParseResponse(page []byte) Response {
+	text := string(page)
+	if m := articleRe.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	text = tagRe.ReplaceAllString(text, "")
+	text = strings.TrimSpace(spaceRe.ReplaceAllString(text, " "))
+
+	r := Response{
+		Verdict: Unknown,
+		Message: text,
+		Wait:    parseWait(text),
+	}
+
+	switch {
+	case strings.Contains(text, "That's the right answer"):
+		r.Verdict = Correct
+	case strings.Contains(text, "your answer is too high"):
+		r.Verdict = TooHigh
+	case strings.Contains(text, "your answer is too low"):
+		r.Verdict = TooLow
+	case strings.Contains(text, "That's not the right answer"):
+		r.Verdict = Wrong
+	case strings.Contains(text, "You gave an answer too recently"):
+		r.Verdict = RateLimited
+	case strings.Contains(text, "You don't seem to be solving the right level"):
+		r.Verdict = WrongLevel
+	}
+
+	return r
+}
+
+func parseWait(text string) time.Duration {
+	m := waitRe.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+
+	if m[4] != "" {
+		if m[4] == "one" {
+			return time.Minute
+		}
+		n, _ := strconv.Atoi(m[4])
+		return time.Duration(n) * time.Minute
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		if m[i+1] != "" {
+			n, _ := strconv.Atoi(m[i+1])
+			d += time.Duration(n) * unit
+		}
+	}
+	return d
+}
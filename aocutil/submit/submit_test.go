@@ -0,0 +1,139 @@
+package submit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"libdb.so/aoc-2023/aocutil/fetch"
+)
+
+const pageFormat = `<!DOCTYPE html><html><body><main>
+<article><p>%s</p></article>
+</main></body></html>`
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		article string
+		verdict Verdict
+		wait    time.Duration
+	}{
+		{
+			`That's the right answer!  You are <span class="day-success">one gold star</span> closer to restoring snow operations. <a href="/2023/day/1#part2">[Continue to Part Two]</a>`,
+			Correct, 0,
+		},
+		{
+			`That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data; please wait one minute before trying again. <a href="/2023/day/1">[Return to Day 1]</a>`,
+			TooHigh, time.Minute,
+		},
+		{
+			`That's not the right answer; your answer is too low.  please wait 5 minutes before trying again.`,
+			TooLow, 5 * time.Minute,
+		},
+		{
+			`That's not the right answer.  If you're stuck, make sure you're using the full input data.`,
+			Wrong, 0,
+		},
+		{
+			`You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 4m 12s left to wait. <a href="/2023/day/1">[Return to Day 1]</a>`,
+			RateLimited, 4*time.Minute + 12*time.Second,
+		},
+		{
+			`You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 33s left to wait.`,
+			RateLimited, 33 * time.Second,
+		},
+		{
+			`You don't seem to be solving the right level.  Did you already complete it? <a href="/2023/day/1">[Return to Day 1]</a>`,
+			WrongLevel, 0,
+		},
+	}
+
+	for _, test := range tests {
+		r := ParseResponse([]byte(fmtPage(test.article)))
+		assert.Equal(t, test.verdict, r.Verdict, r.Message)
+		assert.Equal(t, test.wait, r.Wait, r.Message)
+	}
+}
+
+func fmtPage(article string) string {
+	return fmt.Sprintf(pageFormat, article)
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HistoryFile)
+
+	h, err := LoadHistory(path)
+	assert.NoError(t, err)
+	assert.NoError(t, h.Check(1, "100"))
+
+	for _, g := range []Guess{
+		{Part: 1, Answer: "100", Verdict: TooLow},
+		{Part: 1, Answer: "500", Verdict: TooHigh},
+		{Part: 1, Answer: "300", Verdict: TooHigh},
+		{Part: 1, Answer: "abc", Verdict: Wrong},
+		{Part: 2, Answer: "42", Verdict: Correct},
+	} {
+		assert.NoError(t, h.Record(g))
+	}
+
+	h, err = LoadHistory(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(h.Guesses))
+
+	lower, upper := h.Bounds(1)
+	assert.Equal(t, "100", lower.String())
+	assert.Equal(t, "300", upper.String())
+
+	assert.NoError(t, h.Check(1, "200"))
+	assert.NoError(t, h.Check(1, "xyz"))
+	for _, answer := range []string{"100", "50", "300", "400", "abc"} {
+		assert.IsError(t, h.Check(1, answer), ErrRefused, answer)
+	}
+	assert.IsError(t, h.Check(2, "43"), ErrRefused)
+}
+
+func TestSubmit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2023/day/3/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("level") != "2" {
+			w.Write([]byte(fmtPage(`You don't seem to be solving the right level.`)))
+			return
+		}
+		switch r.FormValue("answer") {
+		case "467835":
+			w.Write([]byte(fmtPage(`That's the right answer!`)))
+		default:
+			w.Write([]byte(fmtPage(`That's not the right answer; your answer is too low.`)))
+		}
+	}))
+	defer srv.Close()
+
+	client := fetch.NewClient("hunter2", nil)
+	client.BaseURL = srv.URL
+	client.MinInterval = 0
+
+	ctx := context.Background()
+
+	r, err := Submit(ctx, client, 3, 2, "467835")
+	assert.NoError(t, err)
+	assert.Equal(t, Correct, r.Verdict)
+
+	r, err = Submit(ctx, client, 3, 2, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, TooLow, r.Verdict)
+
+	r, err = Submit(ctx, client, 3, 1, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, WrongLevel, r.Verdict)
+
+	_, err = Submit(ctx, client, 4, 1, "1")
+	assert.Error(t, err)
+}
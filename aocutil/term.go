@@ -0,0 +1,238 @@
+package aocutil
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteGrid is a 2D grid of bytes, such as Map2D.
+type ByteGrid interface {
+	// At returns the byte at the given point.
+	At(image.Point) byte
+	// Rect returns the bounds of the grid.
+	Rect() image.Rectangle
+}
+
+var _ ByteGrid = Map2D{}
+
+// Rect returns the bounds of the map. It is the same as m.Bounds.
+func (m Map2D) Rect() image.Rectangle { return m.Bounds }
+
+// Highlight is a set of points that is drawn over a grid.
+type Highlight struct {
+	Points []image.Point
+	// Color is the background color of the points.
+	Color color.RGBA
+	// Glyph replaces the glyph of the points if it is not empty.
+	Glyph string
+	// Path connects consecutive points with a line when drawn to an image
+	// with Map2D.DrawWith.
+	Path bool
+}
+
+// TermOptions are options for NewTermAnimation.
+type TermOptions struct {
+	// FPS is the maximum number of frames drawn per second. If it is 0, then
+	// frames are drawn as fast as they come.
+	FPS float64
+	// Colors maps bytes to their foreground colors, like the colorMap given to
+	// Map2D.Draw. Bytes that are not in the map use the default color.
+	Colors map[byte]color.RGBA
+	// Glyphs maps bytes to the strings that are drawn for them, e.g. '#' to
+	// "█". Bytes that are not in the map are drawn as themselves.
+	Glyphs map[byte]string
+	// Width and Height are the size of the viewport in cells. They default to
+	// the size of the terminal, or 80x24 if it is unknown.
+	Width, Height int
+}
+
+// TermAnimation draws successive frames of a grid in place on a terminal. If
+// the output is not a terminal, then each frame is written as plain text
+// followed by an empty line.
+type TermAnimation struct {
+	w    io.Writer
+	opts TermOptions
+	tty  bool
+
+	frames int
+	last   time.Time
+	// lines is the number of lines of the last frame drawn in place.
+	lines int
+	// origin is the top-left point of the viewport.
+	origin image.Point
+	focus  *image.Point
+}
+
+// NewTermAnimation creates a new TermAnimation that draws to w, which is
+// usually os.Stdout or os.Stderr.
+func NewTermAnimation(w io.Writer, opts TermOptions) *TermAnimation {
+	if opts.Width == 0 || opts.Height == 0 {
+		width, height := terminalSize()
+		if opts.Width == 0 {
+			opts.Width = width
+		}
+		if opts.Height == 0 {
+			// Leave a line for the status line and one for the cursor.
+			opts.Height = height - 2
+		}
+	}
+	return &TermAnimation{
+		w:    w,
+		opts: opts,
+		tty:  isTerminal(w),
+	}
+}
+
+// terminalSize returns the terminal size from $COLUMNS and $LINES, falling
+// back to 80x24.
+func terminalSize() (width, height int) {
+	width, height = 80, 24
+	if v, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && v > 0 {
+		width = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("LINES")); err == nil && v > 0 {
+		height = v
+	}
+	return
+}
+
+// Focus scrolls the viewport so that pt is in its center for the next frames.
+// It only matters if the grid is larger than the viewport.
+func (a *TermAnimation) Focus(pt image.Point) {
+	a.focus = &pt
+}
+
+// Scroll moves the viewport by d cells.
+func (a *TermAnimation) Scroll(d image.Point) {
+	a.focus = nil
+	a.origin = a.origin.Add(d)
+}
+
+// Viewport returns the part of the grid bounds that is drawn.
+func (a *TermAnimation) Viewport(bounds image.Rectangle) image.Rectangle {
+	size := image.Pt(
+		Min2(a.opts.Width, bounds.Dx()),
+		Max2(Min2(a.opts.Height, bounds.Dy()), 1),
+	)
+
+	origin := a.origin
+	if a.focus != nil {
+		origin = a.focus.Sub(size.Div(2))
+	}
+	origin.X = Clamp(origin.X, bounds.Min.X, bounds.Max.X-size.X)
+	origin.Y = Clamp(origin.Y, bounds.Min.Y, bounds.Max.Y-size.Y)
+	a.origin = origin
+
+	return image.Rectangle{Min: origin, Max: origin.Add(size)}
+}
+
+// Frame draws the grid with the given highlights over it, replacing the
+// previous frame. Later highlights are drawn over earlier ones. It blocks as
+// long as needed to respect the frame rate.
+func (a *TermAnimation) Frame(g ByteGrid, highlights ...Highlight) {
+	a.frames++
+
+	bounds := g.Rect()
+	view := a.Viewport(bounds)
+
+	overlay := make(map[image.Point]*Highlight)
+	for i := range highlights {
+		for _, pt := range highlights[i].Points {
+			if pt.In(view) {
+				overlay[pt] = &highlights[i]
+			}
+		}
+	}
+
+	var b strings.Builder
+	if a.tty && a.lines > 0 {
+		// Move back to the start of the previous frame.
+		fmt.Fprintf(&b, "\033[%dA\r", a.lines)
+	}
+	if a.tty && a.frames == 1 {
+		b.WriteString("\033[?25l") // hide the cursor
+	}
+
+	for y := view.Min.Y; y < view.Max.Y; y++ {
+		for x := view.Min.X; x < view.Max.X; x++ {
+			pt := image.Pt(x, y)
+			a.writeCell(&b, g.At(pt), overlay[pt])
+		}
+		if a.tty {
+			b.WriteString("\033[K") // clear the rest of the line
+		}
+		b.WriteByte('\n')
+	}
+
+	if a.tty {
+		fmt.Fprintf(&b, "frame %d", a.frames)
+		if view != bounds {
+			fmt.Fprintf(&b, ", viewing %v of %v", view, bounds)
+		}
+		b.WriteString("\033[K\n")
+		a.lines = view.Dy() + 1
+	} else {
+		b.WriteByte('\n')
+	}
+
+	a.wait()
+	io.WriteString(a.w, b.String())
+}
+
+func (a *TermAnimation) writeCell(b *strings.Builder, v byte, hl *Highlight) {
+	glyph, ok := a.opts.Glyphs[v]
+	if !ok {
+		if v == 0 {
+			glyph = " "
+		} else {
+			glyph = string(v)
+		}
+	}
+	if hl != nil && hl.Glyph != "" {
+		glyph = hl.Glyph
+	}
+
+	if !a.tty {
+		b.WriteString(glyph)
+		return
+	}
+
+	var styled bool
+	if c, ok := a.opts.Colors[v]; ok {
+		fmt.Fprintf(b, "\033[38;2;%d;%d;%dm", c.R, c.G, c.B)
+		styled = true
+	}
+	if hl != nil {
+		fmt.Fprintf(b, "\033[48;2;%d;%d;%dm", hl.Color.R, hl.Color.G, hl.Color.B)
+		styled = true
+	}
+	b.WriteString(glyph)
+	if styled {
+		b.WriteString("\033[0m")
+	}
+}
+
+// wait sleeps until the next frame is due.
+func (a *TermAnimation) wait() {
+	if !a.tty || a.opts.FPS <= 0 {
+		return
+	}
+	interval := time.Duration(float64(time.Second) / a.opts.FPS)
+	if d := interval - time.Since(a.last); d > 0 {
+		time.Sleep(d)
+	}
+	a.last = time.Now()
+}
+
+// Close restores the terminal after the last frame.
+func (a *TermAnimation) Close() {
+	if a.tty && a.frames > 0 {
+		io.WriteString(a.w, "\033[?25h") // show the cursor
+	}
+}
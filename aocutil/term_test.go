@@ -0,0 +1,67 @@
+package aocutil
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestTermAnimationPlain(t *testing.T) {
+	var out strings.Builder
+	a := NewTermAnimation(&out, TermOptions{
+		Glyphs: map[byte]string{'#': "█"},
+		Width:  80,
+		Height: 24,
+	})
+
+	m := NewMap2D("#..\n.#.\n..#\n")
+	a.Frame(m)
+	m.Set(image.Pt(1, 1), '.')
+	a.Frame(m, Highlight{Points: []image.Point{{1, 1}}, Glyph: "*"})
+	a.Close()
+
+	assert.Equal(t, ""+
+		"█..\n.█.\n..█\n\n"+
+		"█..\n.*.\n..█\n\n",
+		out.String())
+}
+
+func TestTermAnimationViewport(t *testing.T) {
+	var out strings.Builder
+	a := NewTermAnimation(&out, TermOptions{Width: 2, Height: 2})
+
+	m := NewMap2D("abcd\nefgh\nijkl\nmnop\n")
+	a.Frame(m)
+	a.Focus(image.Pt(3, 3))
+	a.Frame(m)
+	a.Focus(image.Pt(2, 1))
+	a.Frame(m)
+
+	assert.Equal(t, "ab\nef\n\nkl\nop\n\nbc\nfg\n\n", out.String())
+}
+
+func TestTermAnimationANSI(t *testing.T) {
+	var out strings.Builder
+	a := NewTermAnimation(&out, TermOptions{
+		Colors: map[byte]color.RGBA{'#': {255, 0, 0, 255}},
+		Width:  1,
+		Height: 1,
+	})
+	a.tty = true
+
+	m := NewMap2D("#.\n")
+	a.Frame(m)
+	a.Frame(m, Highlight{Points: []image.Point{{0, 0}}, Color: color.RGBA{0, 0, 255, 255}})
+	a.Close()
+
+	frames := strings.Split(out.String(), "frame ")
+	assert.Equal(t, 3, len(frames))
+	assert.Contains(t, frames[0], "\033[?25l\033[38;2;255;0;0m#\033[0m")
+	assert.Contains(t, frames[1], "\033[2A\r")
+	assert.Contains(t, frames[1], "\033[38;2;255;0;0m\033[48;2;0;0;255m#\033[0m")
+	assert.Contains(t, frames[2], "viewing (0,0)-(1,1) of (0,0)-(2,1)")
+	assert.Contains(t, frames[2], "\033[?25h")
+}
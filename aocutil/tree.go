@@ -1,14 +1,26 @@
 package aocutil
 
-import "slices"
+import (
+	"context"
+	"slices"
+)
 
 // DFS is a depth-first search iterator. It takes a root node and a function
 // that returns the children of a node. It returns an iterator that yields
 // nodes in depth-first order.
 func DFS[T any](root T, children func(T) []T) Iter[T] {
+	return DFSContext(context.Background(), root, children)
+}
+
+// DFSContext is like DFS, except it stops yielding once ctx is done.
+func DFSContext[T any](ctx context.Context, root T, children func(T) []T) Iter[T] {
 	return func(yield func(T) bool) {
+		var i int
 		stack := []T{root}
 		for len(stack) > 0 {
+			if Canceled(ctx, &i) {
+				break
+			}
 			node := stack[len(stack)-1]
 			stack = stack[:len(stack)-1]
 
@@ -25,10 +37,20 @@ func DFS[T any](root T, children func(T) []T) Iter[T] {
 
 // AcyclicDFS is a depth-first search iterator that only visits each node once.
 func AcyclicDFS[T comparable](root T, children func(T) []T) Iter[T] {
+	return AcyclicDFSContext(context.Background(), root, children)
+}
+
+// AcyclicDFSContext is like AcyclicDFS, except it stops yielding once ctx is
+// done.
+func AcyclicDFSContext[T comparable](ctx context.Context, root T, children func(T) []T) Iter[T] {
 	return func(yield func(T) bool) {
 		seen := NewSet[T](0)
+		var i int
 		stack := []T{root}
 		for len(stack) > 0 {
+			if Canceled(ctx, &i) {
+				break
+			}
 			node := stack[len(stack)-1]
 			stack = stack[:len(stack)-1]
 
@@ -51,11 +73,20 @@ func AcyclicDFS[T comparable](root T, children func(T) []T) Iter[T] {
 // that returns the children of a node. It returns an iterator that yields
 // nodes in breadth-first order.
 func BFS[T any](root T, children func(T) []T) Iter[T] {
+	return BFSContext(context.Background(), root, children)
+}
+
+// BFSContext is like BFS, except it stops yielding once ctx is done.
+func BFSContext[T any](ctx context.Context, root T, children func(T) []T) Iter[T] {
 	return func(yield func(T) bool) {
 		var z T
 
+		var i int
 		queue := []T{root}
 		for len(queue) > 0 {
+			if Canceled(ctx, &i) {
+				break
+			}
 			node := queue[0]
 
 			// Prevent memory leaks.
@@ -76,12 +107,22 @@ func BFS[T any](root T, children func(T) []T) Iter[T] {
 // AcyclicBFS is a breadth-first search iterator that only visits each node
 // once.
 func AcyclicBFS[T comparable](root T, children func(T) []T) Iter[T] {
+	return AcyclicBFSContext(context.Background(), root, children)
+}
+
+// AcyclicBFSContext is like AcyclicBFS, except it stops yielding once ctx is
+// done.
+func AcyclicBFSContext[T comparable](ctx context.Context, root T, children func(T) []T) Iter[T] {
 	return func(yield func(T) bool) {
 		var z T
 
 		seen := NewSet[T](0)
+		var i int
 		queue := []T{root}
 		for len(queue) > 0 {
+			if Canceled(ctx, &i) {
+				break
+			}
 			node := queue[0]
 
 			// Prevent memory leaks.
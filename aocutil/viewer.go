@@ -0,0 +1,251 @@
+package aocutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Image viewers that OpenImage understands. Any other viewer is a command
+// that is run with the path to the image appended.
+const (
+	ViewerAuto  = "auto"  // pick one of the others from the environment
+	ViewerOpen  = "open"  // xdg-open, or open on macOS
+	ViewerKitty = "kitty" // inline using the Kitty graphics protocol
+	ViewerITerm = "iterm" // inline using the iTerm2 protocol
+	ViewerSixel = "sixel" // inline using Sixel graphics
+	ViewerSave  = "save"  // only save the image and log its path
+)
+
+var (
+	imageViewer = envOr("AOC_IMAGE_VIEWER", ViewerAuto)
+	imageDir    = envOr("AOC_IMAGE_DIR", filepath.Join(os.TempDir(), "aocutil"))
+)
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// OpenImage saves the given image to a PNG image in the image directory and
+// shows it using the viewer given by -image-viewer or $AOC_IMAGE_VIEWER. The
+// image is always saved and its path logged, so it can still be found if
+// there is no way to show it; failing to show it is never fatal.
+func OpenImage(img image.Image) {
+	if err := os.MkdirAll(imageDir, 0755); err != nil {
+		slog.Warn("cannot create the image directory", "err", err)
+		return
+	}
+
+	f, err := os.CreateTemp(imageDir, "aocutil-*.png")
+	if err != nil {
+		slog.Warn("cannot save image", "err", err)
+		return
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	if err := png.Encode(io.MultiWriter(f, &buf), img); err != nil {
+		slog.Warn("cannot save image", "err", err)
+		return
+	}
+
+	// A blank viewer would be a command without a name, so it is treated like
+	// an unset one.
+	viewer := strings.TrimSpace(imageViewer)
+	if viewer == "" || viewer == ViewerAuto {
+		viewer = detectImageViewer()
+	}
+
+	if viewer == ViewerSave {
+		// The path is the only way to find the image, so it is logged even
+		// if logging is silenced by -s.
+		slog.Warn("saved image", "path", f.Name())
+		return
+	}
+
+	slog.Info("saved image", "path", f.Name(), "viewer", viewer)
+
+	if err := showImage(viewer, f.Name(), img, buf.Bytes()); err != nil {
+		slog.Warn("cannot show image, it is only saved", "path", f.Name(), "viewer", viewer, "err", err)
+	}
+}
+
+// detectImageViewer picks the best viewer for the current environment.
+func detectImageViewer() string {
+	if isTerminal(os.Stderr) {
+		switch {
+		case os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("TERM") == "xterm-kitty":
+			return ViewerKitty
+		case os.Getenv("TERM_PROGRAM") == "iTerm.app" || os.Getenv("TERM_PROGRAM") == "WezTerm":
+			return ViewerITerm
+		}
+	}
+	if hasDisplay() {
+		if _, err := exec.LookPath(openCommand()); err == nil {
+			return ViewerOpen
+		}
+	}
+	return ViewerSave
+}
+
+func hasDisplay() bool {
+	return runtime.GOOS == "darwin" || os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
+func openCommand() string {
+	if runtime.GOOS == "darwin" {
+		return "open"
+	}
+	return "xdg-open"
+}
+
+func showImage(viewer, path string, img image.Image, pngData []byte) error {
+	switch viewer {
+	case ViewerKitty, ViewerITerm, ViewerSixel:
+		if !isTerminal(os.Stderr) {
+			return fmt.Errorf("stderr is not a terminal")
+		}
+		var b strings.Builder
+		switch viewer {
+		case ViewerKitty:
+			writeKittyImage(&b, pngData)
+		case ViewerITerm:
+			writeITermImage(&b, pngData)
+		case ViewerSixel:
+			writeSixelImage(&b, img)
+		}
+		b.WriteByte('\n')
+		_, err := io.WriteString(os.Stderr, b.String())
+		return err
+	}
+
+	args := strings.Fields(viewer)
+	if viewer == ViewerOpen {
+		args = []string{openCommand()}
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the viewer in the background so that it does not become a zombie.
+	go cmd.Wait()
+	return nil
+}
+
+// writeKittyImage writes a PNG image using the Kitty graphics protocol. The
+// data is sent in chunks of at most 4096 bytes.
+func writeKittyImage(w *strings.Builder, pngData []byte) {
+	data := base64.StdEncoding.EncodeToString(pngData)
+	first := true
+	for len(data) > 0 {
+		chunk := data[:min(len(data), 4096)]
+		data = data[len(chunk):]
+
+		more := 0
+		if len(data) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(w, "\033_Ga=T,f=100,m=%d;%s\033\\", more, chunk)
+			first = false
+		} else {
+			fmt.Fprintf(w, "\033_Gm=%d;%s\033\\", more, chunk)
+		}
+	}
+}
+
+// writeITermImage writes an image using the iTerm2 inline images protocol.
+func writeITermImage(w *strings.Builder, pngData []byte) {
+	fmt.Fprintf(w, "\033]1337;File=inline=1;size=%d:%s\a",
+		len(pngData), base64.StdEncoding.EncodeToString(pngData))
+}
+
+// writeSixelImage writes an image as Sixel graphics. Images that are not
+// paletted are converted to the Plan 9 palette.
+func writeSixelImage(w *strings.Builder, img image.Image) {
+	b := img.Bounds()
+
+	paletted, ok := img.(*image.Paletted)
+	if !ok || len(paletted.Palette) > 256 {
+		paletted = image.NewPaletted(b, palette.Plan9)
+		draw.Draw(paletted, b, img, b.Min, draw.Src)
+	}
+
+	w.WriteString("\033Pq")
+	fmt.Fprintf(w, "\"1;1;%d;%d", b.Dx(), b.Dy())
+	for i, c := range paletted.Palette {
+		cr, cg, cb, _ := c.RGBA()
+		fmt.Fprintf(w, "#%d;2;%d;%d;%d", i, cr*100/0xFFFF, cg*100/0xFFFF, cb*100/0xFFFF)
+	}
+
+	// Each band is 6 rows tall. Within a band, every color is drawn in its own
+	// pass over the columns.
+	sixels := make([]byte, b.Dx())
+	for y0 := b.Min.Y; y0 < b.Max.Y; y0 += 6 {
+		used := make(map[uint8]bool)
+		for y := y0; y < min(y0+6, b.Max.Y); y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				used[paletted.ColorIndexAt(x, y)] = true
+			}
+		}
+
+		first := true
+		for ix := range paletted.Palette {
+			if !used[uint8(ix)] {
+				continue
+			}
+			for x := b.Min.X; x < b.Max.X; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && y0+dy < b.Max.Y; dy++ {
+					if paletted.ColorIndexAt(x, y0+dy) == uint8(ix) {
+						bits |= 1 << dy
+					}
+				}
+				sixels[x-b.Min.X] = '?' + bits
+			}
+
+			if !first {
+				w.WriteByte('$')
+			}
+			first = false
+
+			fmt.Fprintf(w, "#%d", ix)
+			writeSixelRun(w, sixels)
+		}
+		w.WriteByte('-')
+	}
+
+	w.WriteString("\033\\")
+}
+
+// writeSixelRun writes the sixels with run-length encoding.
+func writeSixelRun(w *strings.Builder, sixels []byte) {
+	for i := 0; i < len(sixels); {
+		j := i
+		for j < len(sixels) && sixels[j] == sixels[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(w, "!%d%c", n, sixels[i])
+		} else {
+			w.WriteString(strings.Repeat(string(sixels[i]), n))
+		}
+		i = j
+	}
+}
@@ -0,0 +1,71 @@
+package aocutil
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestOpenImageHeadless(t *testing.T) {
+	for _, viewer := range []string{ViewerSave, ViewerKitty, "aocutil-no-such-viewer --flag", "", "  "} {
+		t.Run(viewer, func(t *testing.T) {
+			dir := t.TempDir()
+			setViewer(t, viewer, dir)
+
+			withLoggingSilenced(func() { OpenImage(image.NewGray(image.Rect(0, 0, 2, 2))) })
+
+			files, err := filepath.Glob(filepath.Join(dir, "aocutil-*.png"))
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(files))
+		})
+	}
+}
+
+func TestOpenImageNoDirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0644))
+	setViewer(t, ViewerSave, filepath.Join(file, "images"))
+
+	withLoggingSilenced(func() { OpenImage(image.NewGray(image.Rect(0, 0, 2, 2))) })
+}
+
+func setViewer(t *testing.T, viewer, dir string) {
+	oldViewer, oldDir := imageViewer, imageDir
+	imageViewer, imageDir = viewer, dir
+	t.Cleanup(func() { imageViewer, imageDir = oldViewer, oldDir })
+}
+
+func TestWriteSixelImage(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, 5, 7), color.Palette{
+		color.Black,
+		color.White,
+	})
+	for x := 0; x < 5; x++ {
+		img.SetColorIndex(x, 0, 1)
+	}
+
+	var b strings.Builder
+	writeSixelImage(&b, img)
+
+	assert.Equal(t, ""+
+		"\033Pq\"1;1;5;7#0;2;0;0;0#1;2;100;100;100"+
+		"#0!5}$#1!5@-"+ // rows 0-5: row 0 is white, the rest black
+		"#0!5@-"+ // row 6 is black
+		"\033\\",
+		b.String())
+}
+
+func TestWriteKittyImage(t *testing.T) {
+	var b strings.Builder
+	writeKittyImage(&b, make([]byte, 4000))
+
+	chunks := strings.Split(b.String(), "\033\\")
+	assert.Equal(t, 3, len(chunks))
+	assert.True(t, strings.HasPrefix(chunks[0], "\033_Ga=T,f=100,m=1;"))
+	assert.True(t, strings.HasPrefix(chunks[1], "\033_Gm=0;"))
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Day is a registered day package.
+type Day struct {
+	// Number is the day number, starting from 1.
+	Number int
+	// Dir is the path to the day's package directory.
+	Dir string
+}
+
+// Name returns the zero-padded day name, e.g. "01".
+func (d Day) Name() string {
+	return fmt.Sprintf("%02d", d.Number)
+}
+
+// Package returns the relative Go package path of the day, e.g. "./01".
+func (d Day) Package() string {
+	return "./" + d.Name()
+}
+
+// Input returns the path to the input file with the given name.
+func (d Day) Input(name string) string {
+	return filepath.Join(d.Dir, name)
+}
+
+// Registry is a list of days sorted by their number.
+type Registry []Day
+
+// DiscoverDays discovers all day packages within the given repository root. A
+// day package is a directory named after its day number containing a main.go.
+func DiscoverDays(root string) (Registry, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var days Registry
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		n, err := strconv.Atoi(entry.Name())
+		if err != nil || n < 1 || n > 25 {
+			continue
+		}
+
+		dir := filepath.Join(root, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, "main.go")); err != nil {
+			continue
+		}
+
+		days = append(days, Day{Number: n, Dir: dir})
+	}
+
+	slices.SortFunc(days, func(a, b Day) int { return a.Number - b.Number })
+	return days, nil
+}
+
+// Get returns the day with the given number.
+func (r Registry) Get(n int) (Day, bool) {
+	i := slices.IndexFunc(r, func(d Day) bool { return d.Number == n })
+	if i == -1 {
+		return Day{}, false
+	}
+	return r[i], true
+}
+
+// Select returns the days matching the given selectors. A selector is either a
+// day number ("5"), an inclusive range ("3-7") or "all". No selectors select
+// all days.
+func (r Registry) Select(selectors []string) (Registry, error) {
+	if len(selectors) == 0 {
+		return r, nil
+	}
+
+	var days Registry
+	for _, sel := range selectors {
+		lo, hi, err := parseDayRange(sel)
+		if err != nil {
+			return nil, err
+		}
+
+		var found bool
+		for _, day := range r {
+			if day.Number < lo || day.Number > hi {
+				continue
+			}
+			if !slices.Contains(days, day) {
+				days = append(days, day)
+			}
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("no days match %q", sel)
+		}
+	}
+
+	slices.SortFunc(days, func(a, b Day) int { return a.Number - b.Number })
+	return days, nil
+}
+
+func parseDayRange(sel string) (lo, hi int, err error) {
+	if sel == "all" {
+		return 1, 25, nil
+	}
+
+	loStr, hiStr, isRange := strings.Cut(sel, "-")
+	if !isRange {
+		hiStr = loStr
+	}
+
+	lo, err1 := strconv.Atoi(loStr)
+	hi, err2 := strconv.Atoi(hiStr)
+	if err := errors.Join(err1, err2); err != nil {
+		return 0, 0, fmt.Errorf("invalid day selector %q", sel)
+	}
+	if lo > hi {
+		return 0, 0, fmt.Errorf("invalid day range %q: %d > %d", sel, lo, hi)
+	}
+
+	return lo, hi, nil
+}
+
+// discoverRegistry discovers the days within root, or within the repository
+// root if root is empty.
+func discoverRegistry(root string) (Registry, error) {
+	if root == "" {
+		r, err := findRoot()
+		if err != nil {
+			return nil, err
+		}
+		root = r
+	}
+
+	days, err := DiscoverDays(root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot discover days: %w", err)
+	}
+	return days, nil
+}
+
+// findRoot finds the repository root by walking up from the working directory
+// until a go.mod is found.
+func findRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("cannot find go.mod in any parent directory")
+		}
+		dir = parent
+	}
+}
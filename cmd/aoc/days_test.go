@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRegistrySelect(t *testing.T) {
+	var registry Registry
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		registry = append(registry, Day{Number: n})
+	}
+
+	tests := []struct {
+		sel  []string
+		want []int
+		err  bool
+	}{
+		{nil, []int{1, 2, 3, 5, 8, 13}, false},
+		{[]string{"all"}, []int{1, 2, 3, 5, 8, 13}, false},
+		{[]string{"5"}, []int{5}, false},
+		{[]string{"2-6"}, []int{2, 3, 5}, false},
+		{[]string{"13", "1-2", "2"}, []int{1, 2, 13}, false},
+		{[]string{"4"}, nil, true},
+		{[]string{"6-2"}, nil, true},
+		{[]string{"x"}, nil, true},
+	}
+
+	for _, test := range tests {
+		days, err := registry.Select(test.sel)
+		if test.err {
+			assert.Error(t, err, "%v", test.sel)
+			continue
+		}
+		assert.NoError(t, err, "%v", test.sel)
+
+		var got []int
+		for _, day := range days {
+			got = append(got, day.Number)
+		}
+		assert.Equal(t, test.want, got, "%v", test.sel)
+	}
+}
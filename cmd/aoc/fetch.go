@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+
+	"libdb.so/aoc-2023/aocutil/fetch"
+)
+
+func fetchCommand(args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	root := flags.String("root", "", "repository root, defaults to the nearest go.mod")
+	force := flags.Bool("f", false, "overwrite existing input files")
+	flags.Parse(args)
+
+	registry, err := discoverRegistry(*root)
+	if err != nil {
+		return err
+	}
+
+	days, err := registry.Select(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	client, err := fetch.ClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if err := fetchInput(client, day, *force); err != nil {
+			return fmt.Errorf("day %d: %w", day.Number, err)
+		}
+	}
+
+	return nil
+}
+
+// fetchInput writes the input of the day into its directory unless it already
+// exists.
+func fetchInput(client *fetch.Client, day Day, force bool) error {
+	path := day.Input("input")
+	if !force {
+		_, err := os.Stat(path)
+		if err == nil {
+			log.Printf("day %d: %s already exists, skipping", day.Number, path)
+			return nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	input, err := client.Input(context.Background(), day.Number)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, input, 0o644); err != nil {
+		return err
+	}
+
+	log.Printf("day %d: wrote %s", day.Number, path)
+	return nil
+}
+
+// fetchDayInput fetches the input of a single day using the client from the
+// environment.
+func fetchDayInput(day Day) error {
+	client, err := fetch.ClientFromEnv()
+	if err != nil {
+		return err
+	}
+	return fetchInput(client, day, false)
+}
@@ -0,0 +1,57 @@
+// Command aoc runs, manages and scaffolds the Advent of Code days in this
+// repository.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"run", "run [flags] [days...] [-- day flags]", runCommand},
+	{"fetch", "fetch [flags] [days...]", fetchCommand},
+	{"new", "new [flags] DAY", newCommand},
+	{"submit", "submit [flags] DAY PART [ANSWER]", submitCommand},
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("aoc: ")
+
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			if err := cmd.run(args[1:]); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+	}
+
+	log.Printf("unknown command %q", args[0])
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(flag.CommandLine.Output(), "Usage:")
+	for _, cmd := range commands {
+		fmt.Fprintln(flag.CommandLine.Output(), "  aoc", cmd.usage)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+var templates = template.Must(template.ParseFS(templatesFS, "templates/*.tmpl"))
+
+// dayTemplate is the data given to the day templates. The zero value renders
+// the same code as template.go at the repository root.
+type dayTemplate struct {
+	// Parse uses ParseAndRun instead of Run.
+	Parse bool
+	// Map parses the input as a Map2D.
+	Map bool
+}
+
+// InputType returns the type of the input given to each part.
+func (t dayTemplate) InputType() string {
+	if t.Parse {
+		return "T"
+	}
+	return "string"
+}
+
+// dayFiles maps each file created by aoc new to its template.
+var dayFiles = map[string]string{
+	"main.go":      "main.go.tmpl",
+	"main_test.go": "main_test.go.tmpl",
+	"answers":      "answers.tmpl",
+}
+
+func newCommand(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	root := flags.String("root", "", "repository root, defaults to the nearest go.mod")
+	parse := flags.Bool("parse", false, "use ParseAndRun instead of Run")
+	parseMap := flags.Bool("map", false, "parse the input as a Map2D")
+	fetchInput := flags.Bool("fetch", false, "fetch the input of the new day")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aoc new [flags] DAY")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	n, err := strconv.Atoi(flags.Arg(0))
+	if err != nil || n < 1 || n > 25 {
+		return fmt.Errorf("invalid day %q", flags.Arg(0))
+	}
+
+	if *root == "" {
+		r, err := findRoot()
+		if err != nil {
+			return err
+		}
+		*root = r
+	}
+
+	day := Day{Number: n}
+	day.Dir = filepath.Join(*root, day.Name())
+
+	data := dayTemplate{
+		Parse: *parse,
+		Map:   *parseMap,
+	}
+
+	if err := createDay(day, data); err != nil {
+		return err
+	}
+	log.Printf("day %d: created %s", day.Number, day.Dir)
+
+	if *fetchInput {
+		return fetchDayInput(day)
+	}
+	return nil
+}
+
+// createDay creates the directory of the day and renders its files into it.
+func createDay(day Day, data dayTemplate) error {
+	if err := os.Mkdir(day.Dir, 0o755); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("day %d already exists", day.Number)
+		}
+		return err
+	}
+
+	for name, tmpl := range dayFiles {
+		b, err := renderDayFile(tmpl, data)
+		if err != nil {
+			return fmt.Errorf("cannot render %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(day.Dir, name), b, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func renderDayFile(tmpl string, data dayTemplate) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, tmpl, data); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(tmpl, ".go.tmpl") {
+		return buf.Bytes(), nil
+	}
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestDefaultTemplateMatchesTemplateGo(t *testing.T) {
+	want, err := os.ReadFile("../../template.go")
+	assert.NoError(t, err)
+
+	got, err := renderDayFile("main.go.tmpl", dayTemplate{})
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestCreateDay(t *testing.T) {
+	styles := []dayTemplate{
+		{},
+		{Parse: true},
+		{Map: true},
+		{Parse: true, Map: true},
+	}
+
+	for _, style := range styles {
+		day := Day{Number: 25, Dir: filepath.Join(t.TempDir(), "25")}
+		assert.NoError(t, createDay(day, style))
+
+		for name := range dayFiles {
+			_, err := os.Stat(filepath.Join(day.Dir, name))
+			assert.NoError(t, err, name)
+		}
+
+		assert.Error(t, createDay(day, style), "existing days must not be overwritten")
+	}
+}
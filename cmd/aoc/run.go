@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"libdb.so/aoc-2023/aocutil/result"
+)
+
+type dayResult struct {
+	Day     Day
+	Parts   []result.Part
+	Elapsed time.Duration
+	Err     error
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	root := flags.String("root", "", "repository root, defaults to the nearest go.mod")
+	input := flags.String("input", "input", "name of the input file within each day")
+	allInputs := flags.Bool("all-inputs", false, "run every input file of each day instead of -input")
+	verbose := flags.Bool("v", false, "show the days' log output")
+	timeParts := flags.Bool("time", false, "report parse time and memory usage of each part")
+	benchRuns := flags.Int("bench", 0, "run each part `N` times and report min/median/p95")
+	raceCheck := flags.Bool("race-check", false, "report parts that mutate their parsed input")
+	timeout := flags.Duration("timeout", 0, "stop each part after the given duration")
+	flags.Parse(args)
+
+	selectors := flags.Args()
+	var dayArgs []string
+	if i := slices.Index(selectors, "--"); i != -1 {
+		dayArgs = selectors[i+1:]
+		selectors = selectors[:i]
+	}
+
+	if *root == "" {
+		r, err := findRoot()
+		if err != nil {
+			return err
+		}
+		*root = r
+	}
+
+	registry, err := discoverRegistry(*root)
+	if err != nil {
+		return err
+	}
+
+	days, err := registry.Select(selectors)
+	if err != nil {
+		return err
+	}
+
+	binDir, err := os.MkdirTemp("", "aoc-run-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(binDir)
+
+	runArgs := []string{"-json"}
+	if !*verbose {
+		runArgs = append(runArgs, "-s")
+	}
+	if *allInputs {
+		runArgs = append(runArgs, "-all-inputs")
+		*input = ""
+	}
+	if *timeParts {
+		runArgs = append(runArgs, "-time")
+	}
+	if *benchRuns > 0 {
+		runArgs = append(runArgs, "-bench", fmt.Sprint(*benchRuns))
+	}
+	if *raceCheck {
+		runArgs = append(runArgs, "-race-check")
+	}
+	if *timeout > 0 {
+		runArgs = append(runArgs, "-timeout", timeout.String())
+	}
+	runArgs = append(runArgs, dayArgs...)
+
+	columns := slices.Clone(baseColumns)
+	if *allInputs {
+		columns = slices.Insert(columns, 1, inputColumn)
+	}
+	if *timeParts {
+		columns = append(columns, timeColumns...)
+	}
+	if *benchRuns > 0 {
+		columns = append(columns, benchColumns...)
+	}
+	if *raceCheck {
+		columns = append(columns, mutatedColumn)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	writeRow(tw, columns, func(c column) string { return c.header })
+
+	var failed bool
+	var total time.Duration
+	for _, day := range days {
+		r := runDay(*root, binDir, day, *input, runArgs)
+		total += r.Elapsed
+		if r.Err != nil {
+			failed = true
+			writeRow(tw, columns, func(c column) string {
+				switch c.header {
+				case "DAY":
+					return day.Name()
+				case "ANSWER":
+					return fmt.Sprint("ERROR: ", r.Err)
+				case "TIME":
+					return formatDuration(r.Elapsed)
+				default:
+					return "-"
+				}
+			})
+		}
+		for _, part := range r.Parts {
+			failed = failed || part.Status == result.Fail || part.TimedOut
+			writeRow(tw, columns, func(c column) string { return c.value(day, part) })
+		}
+	}
+
+	writeRow(tw, columns, func(c column) string {
+		switch c.header {
+		case "DAY":
+			return "total"
+		case "TIME":
+			return formatDuration(total)
+		default:
+			return ""
+		}
+	})
+	tw.Flush()
+
+	if failed {
+		return errors.New("some days failed or gave wrong answers")
+	}
+	return nil
+}
+
+type column struct {
+	header string
+	value  func(Day, result.Part) string
+}
+
+var baseColumns = []column{
+	{"DAY", func(d Day, _ result.Part) string { return d.Name() }},
+	{"PART", func(_ Day, p result.Part) string { return fmt.Sprint(p.Part) }},
+	{"ANSWER", func(_ Day, p result.Part) string { return p.Answer }},
+	{"STATUS", func(_ Day, p result.Part) string {
+		if p.TimedOut {
+			return "TIMEOUT"
+		}
+		return orDash(string(p.Status))
+	}},
+	{"TIME", func(_ Day, p result.Part) string { return formatDuration(p.Duration) }},
+}
+
+var inputColumn = column{"INPUT", func(_ Day, p result.Part) string { return orDash(p.Input) }}
+
+var timeColumns = []column{
+	{"PARSE", func(_ Day, p result.Part) string {
+		if p.Parse == 0 {
+			return "-"
+		}
+		return formatDuration(p.Parse)
+	}},
+	{"ALLOCS", func(_ Day, p result.Part) string {
+		if p.Stats == nil {
+			return "-"
+		}
+		return fmt.Sprint(p.Stats.Allocs)
+	}},
+	{"ALLOCATED", func(_ Day, p result.Part) string {
+		if p.Stats == nil {
+			return "-"
+		}
+		return result.FormatBytes(p.Stats.AllocBytes)
+	}},
+	{"PEAK HEAP", func(_ Day, p result.Part) string {
+		if p.Stats == nil {
+			return "-"
+		}
+		return result.FormatBytes(p.Stats.PeakHeap)
+	}},
+}
+
+var benchColumns = []column{
+	{"MIN", func(_ Day, p result.Part) string {
+		if p.Bench == nil {
+			return "-"
+		}
+		return formatDuration(p.Bench.Min)
+	}},
+	{"MEDIAN", func(_ Day, p result.Part) string {
+		if p.Bench == nil {
+			return "-"
+		}
+		return formatDuration(p.Bench.Median)
+	}},
+	{"P95", func(_ Day, p result.Part) string {
+		if p.Bench == nil {
+			return "-"
+		}
+		return formatDuration(p.Bench.P95)
+	}},
+}
+
+var mutatedColumn = column{"MUTATED", func(_ Day, p result.Part) string {
+	switch {
+	case p.Mutated == nil:
+		// The day does not support -race-check.
+		return "n/a"
+	case *p.Mutated:
+		return "yes"
+	default:
+		return "no"
+	}
+}}
+
+func writeRow(w io.Writer, columns []column, cell func(column) string) {
+	for i, c := range columns {
+		if i > 0 {
+			io.WriteString(w, "\t")
+		}
+		io.WriteString(w, cell(c))
+	}
+	io.WriteString(w, "\n")
+}
+
+// runDay builds the given day into binDir and runs it against its input file.
+// If input is empty, then nothing is given through stdin, and the day is
+// expected to read its inputs on its own.
+func runDay(root, binDir string, day Day, input string, args []string) dayResult {
+	result := dayResult{Day: day}
+
+	bin := filepath.Join(binDir, day.Name())
+
+	build := exec.Command("go", "build", "-o", bin, day.Package())
+	build.Dir = root
+	if out, err := build.CombinedOutput(); err != nil {
+		result.Err = fmt.Errorf("build failed: %s", firstError(out))
+		return result
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = day.Dir
+
+	if input != "" {
+		in, err := os.Open(day.Input(input))
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		defer in.Close()
+		cmd.Stdin = in
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = io.MultiWriter(&stderr, verboseWriter(args))
+
+	start := time.Now()
+	err := cmd.Run()
+	result.Elapsed = time.Since(start)
+
+	result.Parts = parseResults(&stdout)
+	if err != nil && !hasFailedAnswer(result.Parts) {
+		result.Err = fmt.Errorf("%v: %s", err, lastLine(stderr.Bytes()))
+	}
+
+	return result
+}
+
+// verboseWriter returns os.Stderr if the day is not silenced, otherwise it
+// discards everything.
+func verboseWriter(args []string) io.Writer {
+	if slices.Contains(args, "-s") {
+		return io.Discard
+	}
+	return os.Stderr
+}
+
+// parseResults parses the output of a day. Days that print their answers
+// without going through aocutil.Run are still accepted, with each line being
+// treated as the next part's answer.
+func parseResults(r io.Reader) []result.Part {
+	var results []result.Part
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var part result.Part
+		if err := json.Unmarshal(line, &part); err != nil {
+			part = result.Part{
+				Part:     len(results) + 1,
+				Answer:   string(line),
+				Duration: -1,
+			}
+		}
+
+		results = append(results, part)
+	}
+
+	return results
+}
+
+// hasFailedAnswer returns true if any of the parts gave a wrong answer or
+// timed out, in which case the day exits with a non-zero status on its own.
+func hasFailedAnswer(parts []result.Part) bool {
+	return slices.ContainsFunc(parts, func(p result.Part) bool { return p.Status == result.Fail || p.TimedOut })
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		return "-"
+	}
+	return d.Round(time.Microsecond).String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// firstError returns the first line of the go build output that is not a
+// package header.
+func firstError(b []byte) string {
+	for _, line := range bytes.Split(bytes.TrimSpace(b), []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("#")) {
+			return string(line)
+		}
+	}
+	return string(b)
+}
+
+func lastLine(b []byte) string {
+	b = bytes.TrimSpace(b)
+	if i := bytes.LastIndexByte(b, '\n'); i != -1 {
+		b = b[i+1:]
+	}
+	return string(b)
+}
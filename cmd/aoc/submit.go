@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/aoc-2023/aocutil/fetch"
+	"libdb.so/aoc-2023/aocutil/submit"
+)
+
+func submitCommand(args []string) error {
+	flags := flag.NewFlagSet("submit", flag.ExitOnError)
+	root := flags.String("root", "", "repository root, defaults to the nearest go.mod")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: aoc submit [flags] DAY PART [ANSWER]")
+		fmt.Fprintln(flags.Output(), "If ANSWER is omitted, it is computed by running the day on its input.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 2 || flags.NArg() > 3 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if *root == "" {
+		r, err := findRoot()
+		if err != nil {
+			return err
+		}
+		*root = r
+	}
+
+	registry, err := discoverRegistry(*root)
+	if err != nil {
+		return err
+	}
+
+	dayNum, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid day %q", flags.Arg(0))
+	}
+	day, ok := registry.Get(dayNum)
+	if !ok {
+		return fmt.Errorf("no day %d", dayNum)
+	}
+
+	part, err := strconv.Atoi(flags.Arg(1))
+	if err != nil || (part != 1 && part != 2) {
+		return fmt.Errorf("invalid part %q", flags.Arg(1))
+	}
+
+	answer := flags.Arg(2)
+	if answer == "" {
+		answer, err = computeAnswer(*root, day, part)
+		if err != nil {
+			return err
+		}
+		log.Printf("day %d part %d: computed answer %s", day.Number, part, answer)
+	}
+
+	history, err := submit.LoadHistory(day.Input(submit.HistoryFile))
+	if err != nil {
+		return err
+	}
+	if err := history.Check(part, answer); err != nil {
+		return err
+	}
+
+	client, err := fetch.ClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	resp, err := submit.Submit(context.Background(), client, day.Number, part, answer)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(resp.Message)
+
+	if err := history.Record(submit.Guess{
+		Part:    part,
+		Answer:  answer,
+		Verdict: resp.Verdict,
+		Time:    time.Now(),
+	}); err != nil {
+		return fmt.Errorf("cannot record guess: %w", err)
+	}
+
+	switch {
+	case resp.Verdict == submit.Correct:
+		return recordAnswer(day, part, answer)
+	case resp.Wait > 0:
+		return fmt.Errorf("%s, wait %v before submitting again", resp.Verdict, resp.Wait)
+	default:
+		return errors.New(string(resp.Verdict))
+	}
+}
+
+// computeAnswer runs the given part of the day against its input.
+func computeAnswer(root string, day Day, part int) (string, error) {
+	binDir, err := os.MkdirTemp("", "aoc-submit-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(binDir)
+
+	r := runDay(root, binDir, day, "input", []string{"-json", "-s", "-" + strconv.Itoa(part)})
+	if r.Err != nil {
+		return "", r.Err
+	}
+	for _, p := range r.Parts {
+		if p.Duration < 0 {
+			// The day does not use aocutil.Run, so it ignored the part flag
+			// and its answers cannot be told apart.
+			return "", fmt.Errorf("day %d does not report which part its answers are for, give the answer explicitly", day.Number)
+		}
+		if p.Part == part {
+			return p.Answer, nil
+		}
+	}
+	return "", fmt.Errorf("day %d did not print an answer for part %d", day.Number, part)
+}
+
+// recordAnswer records the correct answer in the day's answers file so that
+// later runs are verified against it. An existing answer for the same part
+// is replaced.
+func recordAnswer(day Day, part int, answer string) error {
+	path := day.Input("answers")
+	key := fmt.Sprintf("input %d", part)
+	record := key + ": " + answer
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	recorded := false
+	for i, line := range lines {
+		k, old, ok := strings.Cut(line, ":")
+		if !ok || strings.Join(strings.Fields(k), " ") != key {
+			continue
+		}
+		if strings.TrimSpace(old) == answer {
+			return nil
+		}
+		lines[i] = record
+		recorded = true
+	}
+	if !recorded {
+		lines = append(lines, record)
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
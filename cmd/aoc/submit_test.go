@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRecordAnswer(t *testing.T) {
+	day := Day{Number: 1, Dir: t.TempDir()}
+	path := day.Input("answers")
+
+	assert.NoError(t, recordAnswer(day, 1, "42"))
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "input 1: 42\n", string(b))
+
+	// Files without a trailing newline are extended on a new line.
+	assert.NoError(t, os.WriteFile(path, []byte("input-small 1: 7\ninput 1: 42"), 0o644))
+	assert.NoError(t, recordAnswer(day, 2, "100"))
+	// Resubmitting the same answer does not duplicate it, and a different one
+	// replaces it.
+	assert.NoError(t, recordAnswer(day, 2, "100"))
+	assert.NoError(t, recordAnswer(day, 1, "43"))
+
+	b, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "input-small 1: 7\ninput 1: 43\ninput 2: 100\n", string(b))
+}
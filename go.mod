@@ -15,6 +15,8 @@ require (
 	github.com/fxamacker/cbor/v2 v2.5.0
 	github.com/hashicorp/golang-lru/v2 v2.0.7
 	github.com/sourcegraph/conc v0.3.0
+	github.com/tidwall/pinhole v0.0.0-20210130162507-d8644a7c3d19
+	golang.org/x/image v0.6.0
 	gopkg.in/typ.v4 v4.3.0
 )
 
@@ -29,9 +31,7 @@ require (
 	github.com/gonum/matrix v0.0.0-20181209220409-c518dec07be9 // indirect
 	github.com/google/btree v1.1.2 // indirect
 	github.com/hexops/gotextdiff v1.0.3 // indirect
-	github.com/tidwall/pinhole v0.0.0-20210130162507-d8644a7c3d19 // indirect
 	github.com/x448/float16 v0.8.4 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.9.0 // indirect
-	golang.org/x/image v0.6.0 // indirect
 )